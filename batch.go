@@ -0,0 +1,30 @@
+package eventemitter
+
+import "time"
+
+// OnBatch adds a listener receiving the arguments of the emits of the event in
+// batches. A batch is delivered once it holds maxSize emits, or maxWait after
+// its first emit, whichever comes first. A zero limit is ignored; when both are
+// zero, every emit is delivered in its own batch.
+//
+// Batches are delivered asynchronously in every emit mode, one at a time and
+// in order, and the last batch is delivered on shutdown.
+func (e *Emitter) OnBatch(eventName string, maxSize int, maxWait time.Duration, listener func(batch [][]any), options ...ListenerOption) error {
+	options = append(options[:len(options):len(options)], withBatch(maxSize, maxWait))
+
+	return e.AddListener(eventName, listener, options...)
+}
+
+func withBatch(size int, wait time.Duration) ListenerOption {
+	return func(h *handler) {
+		if size <= 0 && wait <= 0 {
+			size = 1
+		}
+
+		c := h.coalescing()
+		c.wait = wait
+		c.throttle = false
+		c.size = size
+		c.coalesce = CoalesceAll
+	}
+}
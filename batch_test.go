@@ -0,0 +1,82 @@
+package eventemitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnBatch(t *testing.T) {
+	emitter := New()
+
+	batches := &calls{}
+	assert.NoError(t, emitter.OnBatch("event", 2, time.Hour, func(batch [][]any) {
+		batches.add(batch)
+	}))
+
+	// Delivered when the size limit is reached.
+	emitter.EmitSync("event", 1)
+	emitter.EmitSync("event", 2, "b")
+	emitter.EmitSync("event", 3)
+	assert.Eventually(t, func() bool {
+		return len(batches.get()) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []any{[][]any{{1}, {2, "b"}}}, batches.get())
+
+	// The last batch is delivered on shutdown.
+	assert.NoError(t, emitter.Close())
+	assert.Equal(t, []any{[][]any{{1}, {2, "b"}}, [][]any{{3}}}, batches.get())
+}
+
+func TestOnBatchWait(t *testing.T) {
+	emitter := New()
+
+	batches := &calls{}
+	emitter.OnBatch("event", 0, 50*time.Millisecond, func(batch [][]any) {
+		batches.add(batch)
+	})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		emitter.Emit("event", i)
+	}
+
+	assert.Eventually(t, func() bool {
+		return len(batches.get()) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, []any{[][]any{{0}, {1}, {2}}}, batches.get())
+
+	// Without limits, every emit is its own batch.
+	single := &calls{}
+	emitter.OnBatch("single", 0, 0, func(batch [][]any) {
+		single.add(batch)
+	})
+	emitter.EmitSync("single", 1)
+	emitter.EmitSync("single", 2)
+
+	assert.NoError(t, emitter.Close())
+	assert.Equal(t, []any{[][]any{{1}}, [][]any{{2}}}, single.get())
+
+	assert.Equal(t, ErrEmptyName, emitter.OnBatch("", 1, 0, func(batch [][]any) {}))
+}
+
+func TestOnBatchOrder(t *testing.T) {
+	emitter := New()
+
+	batches := &calls{}
+	emitter.OnBatch("event", 1, 0, func(batch [][]any) {
+		batches.add(batch[0][0])
+	})
+
+	expected := make([]any, 0, 500)
+	for i := 0; i < 500; i++ {
+		emitter.Emit("event", i)
+		expected = append(expected, i)
+	}
+
+	// Batches are delivered one at a time, in order.
+	assert.NoError(t, emitter.Close())
+	assert.Equal(t, expected, batches.get())
+}
@@ -0,0 +1,557 @@
+/*
+Package bridge connects event emitters of different processes over Unix
+sockets or TCP.
+
+A bridge forwards the emits of the configured events to the connected peers,
+and emits the configured events received from the peers on the local emitter.
+A server relays the events received from a client to the other clients.
+*/
+package bridge
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/attilabuti/eventemitter/v2"
+)
+
+var (
+	ErrClosed       = errors.New("Bridge is closed")
+	ErrBackpressure = errors.New("Send buffer of the bridge is full")
+	ErrFrameSize    = errors.New("Frame exceeds the maximum size")
+)
+
+// maxFrameSize is the maximum size of an encoded message.
+const maxFrameSize = 16 << 20
+
+// remoteKey is the context key marking the events received by a bridge, so
+// they are not sent back.
+type remoteKey struct{}
+
+// Bridge connects an emitter to the emitters of other processes.
+type Bridge struct {
+	emitter *eventemitter.Emitter
+	options options
+	codec   *eventemitter.EventCodec
+	remove  func()
+
+	listener net.Listener // The listener of a server.
+	client   *peer        // The peer of a client.
+
+	mu     sync.Mutex
+	peers  map[*peer]struct{}
+	closed bool
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// Option configures a bridge.
+type Option func(*options)
+
+type options struct {
+	events       []string
+	codec        eventemitter.Codec
+	registry     *eventemitter.Registry
+	buffer       int
+	sendTimeout  time.Duration
+	minReconnect time.Duration
+	maxReconnect time.Duration
+	errors       func(err error)
+}
+
+// peer is a connected process. The frames to send are queued in a bounded
+// buffer, which is kept across the reconnections of a client.
+type peer struct {
+	send    chan []byte
+	pending []byte // Frame not sent before the connection failed.
+}
+
+// WithEvents forwards the emits of the events named eventNames to the peers,
+// and emits the events of these names received from the peers. Other received
+// events are dropped.
+func WithEvents(eventNames ...string) Option {
+	return func(o *options) {
+		o.events = append(o.events, eventNames...)
+	}
+}
+
+// WithCodec sets the codec of the arguments of the events. Defaults to
+// eventemitter.JSONCodec. The peers must use the same codec.
+func WithCodec(codec eventemitter.Codec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
+
+// WithRegistry decodes the arguments of the registered events into their
+// registered types. The peers must use the same registrations.
+func WithRegistry(registry *eventemitter.Registry) Option {
+	return func(o *options) {
+		o.registry = registry
+	}
+}
+
+// WithBuffer sets the number of messages buffered for each peer. Defaults to
+// 64. When the buffer is full, the emits forwarded to the peer wait for space,
+// up to the send timeout.
+func WithBuffer(size int) Option {
+	return func(o *options) {
+		o.buffer = size
+	}
+}
+
+// WithSendTimeout limits the time an emit waits for space in the buffer of a
+// peer, after which the forwarding listener fails with ErrBackpressure.
+// Defaults to waiting until the bridge is closed.
+func WithSendTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.sendTimeout = timeout
+	}
+}
+
+// WithReconnect sets the delays between the reconnection attempts of a client,
+// which are doubled from min up to max. Defaults to 100ms and 5s.
+func WithReconnect(min, max time.Duration) Option {
+	return func(o *options) {
+		o.minReconnect = min
+		o.maxReconnect = max
+	}
+}
+
+// WithErrorHandler sets the function receiving the errors of the messages of
+// the peers that cannot be emitted: messages that cannot be decoded, and events
+// whose arguments don't match the local listeners. Such messages are skipped.
+func WithErrorHandler(handler func(err error)) Option {
+	return func(o *options) {
+		o.errors = handler
+	}
+}
+
+func newBridge(emitter *eventemitter.Emitter, opts []Option) *Bridge {
+	b := &Bridge{
+		emitter: emitter,
+		options: options{
+			codec:        eventemitter.JSONCodec,
+			buffer:       64,
+			minReconnect: 100 * time.Millisecond,
+			maxReconnect: 5 * time.Second,
+		},
+		peers: make(map[*peer]struct{}),
+		done:  make(chan struct{}),
+	}
+
+	for _, option := range opts {
+		option(&b.options)
+	}
+
+	if b.options.buffer < 1 {
+		b.options.buffer = 1
+	}
+
+	b.codec = eventemitter.NewEventCodec(b.options.codec, b.options.registry)
+
+	return b
+}
+
+// Listen starts a bridge server accepting peers on the network address, e.g.
+// Listen(emitter, "unix", "/run/app.sock").
+func Listen(emitter *eventemitter.Emitter, network, address string, options ...Option) (*Bridge, error) {
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newBridge(emitter, options)
+	b.listener = listener
+
+	if err := b.forward(); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	b.wg.Add(1)
+	go b.accept()
+
+	return b, nil
+}
+
+// Dial starts a bridge client connected to the server at the network address.
+// The client reconnects when the connection fails; the emits forwarded in the
+// meantime are buffered. Messages are delivered at most once: those written
+// to a connection shortly before it fails may be lost.
+func Dial(emitter *eventemitter.Emitter, network, address string, options ...Option) (*Bridge, error) {
+	b := newBridge(emitter, options)
+	b.client = b.newPeer()
+
+	// The first connection is established before returning.
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.forward(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	b.wg.Add(1)
+	go b.reconnect(conn, network, address)
+
+	return b, nil
+}
+
+// Addr returns the address of the server, or nil for a client.
+func (b *Bridge) Addr() net.Addr {
+	if b.listener == nil {
+		return nil
+	}
+
+	return b.listener.Addr()
+}
+
+// Close disconnects the peers and stops forwarding the emits. The buffered
+// messages that were not sent are discarded.
+func (b *Bridge) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.done)
+	b.mu.Unlock()
+
+	b.remove()
+
+	var err error
+	if b.listener != nil {
+		err = b.listener.Close()
+	}
+
+	b.wg.Wait()
+
+	return err
+}
+
+// forward adds the listeners forwarding the emits to the peers.
+func (b *Bridge) forward() error {
+	var listeners []*func(ctx context.Context, args ...any) error
+	b.remove = func() {
+		for i, listener := range listeners {
+			b.emitter.RemoveListener(b.options.events[i], listener)
+		}
+	}
+
+	for _, eventName := range b.options.events {
+		eventName := eventName
+
+		listener := func(ctx context.Context, args ...any) error {
+			// Events received from a bridge are not sent back.
+			if ctx.Value(remoteKey{}) != nil {
+				return nil
+			}
+
+			return b.send(eventName, args)
+		}
+
+		if err := b.emitter.AddListener(eventName, &listener, eventemitter.WithEmitContext()); err != nil {
+			b.remove()
+			return err
+		}
+
+		listeners = append(listeners, &listener)
+	}
+
+	return nil
+}
+
+// send queues the event for every peer.
+func (b *Bridge) send(eventName string, arguments []any) error {
+	frame, err := b.codec.Marshal(eventName, arguments)
+	if err != nil {
+		return err
+	}
+
+	if len(frame) > maxFrameSize {
+		return ErrFrameSize
+	}
+
+	if b.client != nil {
+		return b.enqueue(b.client, frame)
+	}
+
+	for _, p := range b.connected() {
+		if err := b.enqueue(p, frame); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// relay queues the frame received from a client for the other clients.
+func (b *Bridge) relay(from *peer, frame []byte) {
+	for _, p := range b.connected() {
+		if p != from {
+			b.enqueue(p, frame)
+		}
+	}
+}
+
+func (b *Bridge) connected() []*peer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	peers := make([]*peer, 0, len(b.peers))
+	for p := range b.peers {
+		peers = append(peers, p)
+	}
+
+	return peers
+}
+
+// enqueue queues the frame for the peer, waiting for space in its buffer up
+// to the send timeout.
+func (b *Bridge) enqueue(p *peer, frame []byte) error {
+	select {
+	case p.send <- frame:
+		return nil
+	default:
+	}
+
+	var timeout <-chan time.Time
+	if b.options.sendTimeout > 0 {
+		timer := time.NewTimer(b.options.sendTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case p.send <- frame:
+		return nil
+	case <-timeout:
+		return ErrBackpressure
+	case <-b.done:
+		return ErrClosed
+	}
+}
+
+func (b *Bridge) newPeer() *peer {
+	return &peer{send: make(chan []byte, b.options.buffer)}
+}
+
+func (b *Bridge) accept() {
+	defer b.wg.Done()
+
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			select {
+			case <-b.done:
+				return
+			default:
+			}
+
+			// Temporary errors, e.g. too many open files.
+			time.Sleep(b.options.minReconnect)
+			continue
+		}
+
+		p := b.newPeer()
+
+		b.mu.Lock()
+		if b.closed {
+			b.mu.Unlock()
+			conn.Close()
+			return
+		}
+		b.peers[p] = struct{}{}
+		b.mu.Unlock()
+
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+
+			b.serve(p, conn)
+
+			b.mu.Lock()
+			delete(b.peers, p)
+			b.mu.Unlock()
+		}()
+	}
+}
+
+// reconnect serves the connection of the client, and reconnects when it
+// fails, until the bridge is closed.
+func (b *Bridge) reconnect(conn net.Conn, network, address string) {
+	defer b.wg.Done()
+
+	delay := b.options.minReconnect
+	for {
+		if conn != nil {
+			b.serve(b.client, conn)
+			delay = b.options.minReconnect
+		}
+
+		select {
+		case <-b.done:
+			return
+		case <-time.After(delay):
+		}
+
+		var err error
+		if conn, err = net.Dial(network, address); err != nil {
+			conn = nil
+
+			if delay *= 2; delay > b.options.maxReconnect {
+				delay = b.options.maxReconnect
+			}
+		}
+	}
+}
+
+// serve exchanges the messages with the peer until the connection fails or
+// the bridge is closed.
+func (b *Bridge) serve(p *peer, conn net.Conn) {
+	defer conn.Close()
+
+	failed := make(chan struct{})
+	go func() {
+		defer close(failed)
+		b.receive(p, conn)
+	}()
+
+	w := bufio.NewWriter(conn)
+	for {
+		frame := p.pending
+		if frame == nil {
+			select {
+			case frame = <-p.send:
+			case <-failed:
+				return
+			case <-b.done:
+				conn.Close()
+				<-failed
+				return
+			}
+		}
+
+		if err := writeFrame(w, frame); err != nil {
+			// The frame is sent again on the next connection.
+			p.pending = frame
+			conn.Close()
+			<-failed
+			return
+		}
+		p.pending = nil
+	}
+}
+
+// receive emits the messages received from the peer.
+func (b *Bridge) receive(p *peer, conn net.Conn) {
+	ctx := context.WithValue(context.Background(), remoteKey{}, b)
+	r := bufio.NewReader(conn)
+
+	for {
+		frame, err := readFrame(r)
+		if err != nil {
+			conn.Close()
+			return
+		}
+
+		eventName, arguments, err := b.codec.Decode(frame)
+		if err != nil {
+			b.failed(err)
+			continue
+		}
+
+		if !b.accepts(eventName) {
+			continue
+		}
+
+		if b.listener != nil {
+			b.relay(p, frame)
+		}
+
+		if err := b.emit(ctx, eventName, arguments); err != nil {
+			b.failed(err)
+		}
+	}
+}
+
+// accepts reports whether the event received from a peer is configured.
+func (b *Bridge) accepts(eventName string) bool {
+	for _, name := range b.options.events {
+		if name == eventName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// emit emits the event received from a peer. Arguments not matching the local
+// listeners are reported instead of panicking, as the errors of the emit,
+// except for events without listeners.
+func (b *Bridge) emit(ctx context.Context, eventName string, arguments []any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Emit of event %s received from a peer failed: %v", eventName, r)
+		}
+	}()
+
+	err = b.emitter.EmitContext(ctx, eventName, arguments...)
+	if err != nil && !errors.Is(err, eventemitter.ErrEventNotExists) {
+		return fmt.Errorf("Emit of event %s received from a peer failed: %w", eventName, err)
+	}
+
+	return nil
+}
+
+func (b *Bridge) failed(err error) {
+	if b.options.errors != nil {
+		b.options.errors(err)
+	}
+}
+
+// writeFrame writes the frame prefixed with its length.
+func writeFrame(w *bufio.Writer, frame []byte) error {
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(frame)))
+
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(frame); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+func readFrame(r io.Reader) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[:])
+	if size > maxFrameSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrFrameSize, size)
+	}
+
+	frame := make([]byte, size)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		return nil, err
+	}
+
+	return frame, nil
+}
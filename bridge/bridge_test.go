@@ -0,0 +1,329 @@
+package bridge
+
+import (
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attilabuti/eventemitter/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// received collects the events received by an emitter.
+type received struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *received) listen(emitter *eventemitter.Emitter, eventName string) {
+	emitter.On(eventName, func(args ...any) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		for _, arg := range args {
+			eventName += ":" + arg.(string)
+		}
+		r.events = append(r.events, eventName)
+	})
+}
+
+func (r *received) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.events...)
+}
+
+func waitFor(t *testing.T, r *received, n int) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		return len(r.get()) >= n
+	}, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestBridgeUnix(t *testing.T) {
+	address := filepath.Join(t.TempDir(), "bridge.sock")
+
+	server := eventemitter.New()
+	serverEvents := &received{}
+	serverEvents.listen(server, "ready")
+
+	s, err := Listen(server, "unix", address, WithEvents("shutdown", "ready"), WithCodec(eventemitter.GobCodec))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer s.Close()
+
+	client := eventemitter.New()
+	clientEvents := &received{}
+	clientEvents.listen(client, "shutdown")
+	clientEvents.listen(client, "restart")
+
+	c, err := Dial(client, "unix", address, WithEvents("ready", "shutdown"), WithCodec(eventemitter.GobCodec))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer c.Close()
+
+	assert.NoError(t, client.EmitSync("ready", "client"))
+	waitFor(t, serverEvents, 1)
+	assert.Equal(t, []string{"ready:client"}, serverEvents.get())
+
+	// Events not forwarded by the server.
+	server.On("restart", func(string) {})
+	server.EmitSync("restart", "server")
+	assert.NoError(t, server.EmitSync("shutdown", "now"))
+	waitFor(t, clientEvents, 1)
+	assert.Equal(t, []string{"shutdown:now"}, clientEvents.get())
+}
+
+func TestBridgeTCP(t *testing.T) {
+	server := eventemitter.New()
+	serverEvents := &received{}
+	serverEvents.listen(server, "ready")
+
+	s, err := Listen(server, "tcp", "127.0.0.1:0", WithEvents("shutdown", "ready"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer s.Close()
+
+	var clients []*received
+	var emitters []*eventemitter.Emitter
+	for i := 0; i < 2; i++ {
+		client := eventemitter.New()
+		events := &received{}
+		events.listen(client, "shutdown")
+		events.listen(client, "ready")
+		clients = append(clients, events)
+		emitters = append(emitters, client)
+
+		c, err := Dial(client, "tcp", s.Addr().String(), WithEvents("ready", "shutdown"))
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer c.Close()
+	}
+
+	assert.Eventually(t, func() bool {
+		return len(s.connected()) == 2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	// Server to clients.
+	assert.NoError(t, server.EmitSync("shutdown", "now"))
+	waitFor(t, clients[0], 1)
+	waitFor(t, clients[1], 1)
+
+	// Client to the server and the other client, without echoes.
+	assert.NoError(t, emitters[0].EmitSync("ready", "first"))
+	waitFor(t, serverEvents, 1)
+	waitFor(t, clients[1], 2)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, []string{"ready:first"}, serverEvents.get())
+	assert.Equal(t, []string{"shutdown:now", "ready:first"}, clients[0].get())
+	assert.Equal(t, []string{"shutdown:now", "ready:first"}, clients[1].get())
+}
+
+func TestBridgeReconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	server := eventemitter.New()
+	s, err := Listen(server, "tcp", address, WithEvents("event"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	client := eventemitter.New()
+	c, err := Dial(client, "tcp", address, WithEvents("event"), WithReconnect(10*time.Millisecond, 50*time.Millisecond))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer c.Close()
+
+	events := &received{}
+	events.listen(server, "event")
+
+	// Emits are buffered while the server is down.
+	assert.NoError(t, s.Close())
+	time.Sleep(20 * time.Millisecond)
+	client.EmitSync("event", "buffered")
+
+	s, err = Listen(server, "tcp", address, WithEvents("event"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer s.Close()
+
+	waitFor(t, events, 1)
+	assert.Equal(t, []string{"event:buffered"}, events.get())
+}
+
+func TestBridgeBackpressure(t *testing.T) {
+	server := eventemitter.New()
+	s, err := Listen(server, "tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	address := s.Addr().String()
+
+	client := eventemitter.New()
+	c, err := Dial(client, "tcp", address, WithEvents("event"), WithBuffer(1), WithSendTimeout(20*time.Millisecond), WithReconnect(time.Hour, time.Hour))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// The server is down, and the client doesn't reconnect.
+	assert.NoError(t, s.Close())
+	time.Sleep(20 * time.Millisecond)
+
+	assert.NoError(t, client.EmitSync("event", "1"))
+
+	var err2 error
+	for i := 0; i < 3 && err2 == nil; i++ {
+		err2 = client.EmitSync("event", "2")
+	}
+	assert.ErrorIs(t, err2, ErrBackpressure)
+
+	assert.NoError(t, c.Close())
+	assert.ErrorIs(t, client.EmitSync("event", "3"), eventemitter.ErrEventNotExists)
+}
+
+func TestBridgeRegistry(t *testing.T) {
+	type order struct {
+		ID     int
+		Status string
+	}
+
+	registry := eventemitter.NewRegistry()
+	registry.Register("order", order{}, 0)
+
+	server := eventemitter.New()
+	received := make(chan order, 1)
+	server.On("order", func(o order, n int) {
+		received <- o
+	})
+
+	s, err := Listen(server, "tcp", "127.0.0.1:0", WithEvents("order"), WithRegistry(registry))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer s.Close()
+
+	client := eventemitter.New()
+	c, err := Dial(client, "tcp", s.Addr().String(), WithEvents("order"), WithRegistry(registry))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer c.Close()
+
+	// Arguments not matching the registered types are rejected.
+	assert.Error(t, client.EmitSync("order", "shipped", 1))
+	assert.NoError(t, client.EmitSync("order", order{1, "shipped"}, 1))
+
+	select {
+	case o := <-received:
+		assert.Equal(t, order{1, "shipped"}, o)
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "event not received")
+	}
+}
+
+func TestBridgeMismatchedArguments(t *testing.T) {
+	server := eventemitter.New()
+	received := make(chan int, 1)
+	server.On("n", func(n int) {
+		received <- n
+	})
+
+	errs := make(chan error, 1)
+	s, err := Listen(server, "tcp", "127.0.0.1:0", WithEvents("n"), WithErrorHandler(func(err error) {
+		errs <- err
+	}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer s.Close()
+
+	client := eventemitter.New()
+	c, err := Dial(client, "tcp", s.Addr().String(), WithEvents("n"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer c.Close()
+
+	// The number is decoded as a float64 by the JSON codec, and is reported
+	// instead of panicking.
+	assert.NoError(t, client.EmitSync("n", 1))
+
+	select {
+	case err := <-errs:
+		assert.Contains(t, err.Error(), "Emit of event n received from a peer failed")
+		assert.Contains(t, err.Error(), "float64")
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "error not reported")
+	}
+
+	// The following messages are emitted.
+	server.RemoveAllListeners("n")
+	server.On("n", func(n float64) {
+		received <- int(n)
+	})
+	assert.NoError(t, client.EmitSync("n", 3.0))
+
+	select {
+	case n := <-received:
+		assert.Equal(t, 3, n)
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "event not received")
+	}
+}
+
+func TestBridgeReceivedEvents(t *testing.T) {
+	server := eventemitter.New()
+	serverEvents := &received{}
+	serverEvents.listen(server, "ready")
+	serverEvents.listen(server, "other")
+	server.On("closed", func(string) {})
+
+	errs := make(chan error, 1)
+	s, err := Listen(server, "tcp", "127.0.0.1:0", WithEvents("ready", "closed"), WithErrorHandler(func(err error) {
+		errs <- err
+	}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer s.Close()
+
+	client := eventemitter.New()
+	c, err := Dial(client, "tcp", s.Addr().String(), WithEvents("other", "closed", "ready"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer c.Close()
+
+	// Events not configured on the server are dropped.
+	assert.NoError(t, client.EmitSync("other", "dropped"))
+	assert.NoError(t, client.EmitSync("ready", "client"))
+	waitFor(t, serverEvents, 1)
+	assert.Equal(t, []string{"ready:client"}, serverEvents.get())
+
+	// Errors of the emits are reported.
+	assert.NoError(t, server.Close())
+	assert.NoError(t, client.EmitSync("closed", "client"))
+
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, eventemitter.ErrClosed)
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "error not reported")
+	}
+}
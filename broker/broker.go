@@ -0,0 +1,241 @@
+/*
+Package broker connects event emitters to a message broker, e.g. NATS, Redis
+or Kafka, so events are shared between processes.
+
+An emitter attached to a broker publishes the emits of the configured events,
+and emits the events published by the other emitters. The local listeners are
+called as without a broker.
+*/
+package broker
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/attilabuti/eventemitter/v2"
+)
+
+var ErrClosed = errors.New("Broker is closed")
+
+// originSize is the size of the ID prefixed to the published messages, so an
+// attachment ignores its own messages.
+const originSize = 16
+
+// remoteKey is the context key marking the events received from a broker, so
+// they are not published again.
+type remoteKey struct{}
+
+// Broker is a message broker.
+type Broker interface {
+	// Publish publishes the data on the subject.
+	Publish(ctx context.Context, subject string, data []byte) error
+	// Subscribe calls the handler with the data published on the subject.
+	Subscribe(subject string, handler func(subject string, data []byte)) (Subscription, error)
+	// Close closes the connection to the broker.
+	Close() error
+}
+
+// Subscription is a subscription to a subject.
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// Attachment is an emitter attached to a broker.
+type Attachment struct {
+	emitter  *eventemitter.Emitter
+	broker   Broker
+	options  options
+	codec    *eventemitter.EventCodec
+	origin   [originSize]byte
+	subjects map[string]string // Subjects of the shared events.
+
+	remove        func()
+	subscriptions []Subscription
+}
+
+// Option configures an attachment.
+type Option func(*options)
+
+type options struct {
+	events   []string
+	prefix   string
+	codec    eventemitter.Codec
+	registry *eventemitter.Registry
+	errors   func(err error)
+}
+
+// WithEvents shares the events named eventNames through the broker.
+func WithEvents(eventNames ...string) Option {
+	return func(o *options) {
+		o.events = append(o.events, eventNames...)
+	}
+}
+
+// WithPrefix sets the prefix of the subjects of the events, e.g. "app.".
+// The subject of an event is its name by default.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithCodec sets the codec of the arguments of the events. Defaults to
+// eventemitter.JSONCodec.
+func WithCodec(codec eventemitter.Codec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
+
+// WithRegistry decodes the arguments of the registered events into their
+// registered types.
+func WithRegistry(registry *eventemitter.Registry) Option {
+	return func(o *options) {
+		o.registry = registry
+	}
+}
+
+// WithErrorHandler sets the function receiving the errors of the attachment:
+// the errors of the publications, and of the messages of other attachments
+// that cannot be emitted, e.g. events whose arguments don't match the local
+// listeners. Such messages are skipped.
+func WithErrorHandler(handler func(err error)) Option {
+	return func(o *options) {
+		o.errors = handler
+	}
+}
+
+// Attach attaches the emitter to the broker. The emits of the configured
+// events are published by an observer of the emitter as they are emitted,
+// without adding listeners, and the events published by other attachments are
+// emitted asynchronously.
+func Attach(emitter *eventemitter.Emitter, broker Broker, opts ...Option) (*Attachment, error) {
+	a := &Attachment{
+		emitter:  emitter,
+		broker:   broker,
+		options:  options{codec: eventemitter.JSONCodec},
+		subjects: make(map[string]string),
+	}
+
+	for _, option := range opts {
+		option(&a.options)
+	}
+
+	a.codec = eventemitter.NewEventCodec(a.options.codec, a.options.registry)
+
+	if _, err := rand.Read(a.origin[:]); err != nil {
+		return nil, err
+	}
+
+	for _, eventName := range a.options.events {
+		a.subjects[eventName] = a.options.prefix + eventName
+	}
+
+	for _, eventName := range a.options.events {
+		subscription, err := a.broker.Subscribe(a.subjects[eventName], a.receive)
+		if err != nil {
+			a.Close()
+			return nil, err
+		}
+
+		a.subscriptions = append(a.subscriptions, subscription)
+	}
+
+	a.remove = emitter.ObserveContext(a.observe)
+
+	return a, nil
+}
+
+// Close detaches the emitter from the broker. The broker is not closed.
+func (a *Attachment) Close() error {
+	if a.remove != nil {
+		a.remove()
+	}
+
+	var err error
+	for _, subscription := range a.subscriptions {
+		if e := subscription.Unsubscribe(); e != nil && err == nil {
+			err = e
+		}
+	}
+	a.subscriptions = nil
+
+	return err
+}
+
+// observe publishes the emits of the shared events.
+func (a *Attachment) observe(ctx context.Context, eventName string, arguments []any) {
+	subject, ok := a.subjects[eventName]
+
+	// Events received from the broker are not published again.
+	if !ok || ctx.Value(remoteKey{}) != nil {
+		return
+	}
+
+	if err := a.publish(ctx, subject, eventName, arguments); err != nil {
+		a.failed(err)
+	}
+}
+
+func (a *Attachment) publish(ctx context.Context, subject, eventName string, arguments []any) error {
+	event, err := a.codec.Marshal(eventName, arguments)
+	if err != nil {
+		return err
+	}
+
+	data := make([]byte, 0, originSize+len(event))
+	data = append(data, a.origin[:]...)
+	data = append(data, event...)
+
+	return a.broker.Publish(ctx, subject, data)
+}
+
+// receive emits the events published by the other attachments.
+func (a *Attachment) receive(subject string, data []byte) {
+	if len(data) < originSize || string(data[:originSize]) == string(a.origin[:]) {
+		return
+	}
+
+	eventName, arguments, err := a.codec.Decode(data[originSize:])
+	if err != nil {
+		a.failed(err)
+		return
+	}
+
+	// Events published on the subject of another event are dropped.
+	if a.subjects[eventName] != subject {
+		return
+	}
+
+	if err := a.emit(eventName, arguments); err != nil {
+		a.failed(err)
+	}
+}
+
+// emit emits the event received from the broker. Arguments not matching the
+// local listeners are reported instead of panicking, so the panic doesn't reach
+// the publisher of a synchronous broker, as the errors of the emit, except for
+// events without listeners.
+func (a *Attachment) emit(eventName string, arguments []any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Emit of event %s received from the broker failed: %v", eventName, r)
+		}
+	}()
+
+	ctx := context.WithValue(context.Background(), remoteKey{}, a)
+	err = a.emitter.EmitContext(ctx, eventName, arguments...)
+	if err != nil && !errors.Is(err, eventemitter.ErrEventNotExists) {
+		return fmt.Errorf("Emit of event %s received from the broker failed: %w", eventName, err)
+	}
+
+	return nil
+}
+
+func (a *Attachment) failed(err error) {
+	if a.options.errors != nil {
+		a.options.errors(err)
+	}
+}
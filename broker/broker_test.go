@@ -0,0 +1,249 @@
+package broker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attilabuti/eventemitter/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// received collects the arguments received by a listener.
+type received struct {
+	mu   sync.Mutex
+	args []any
+}
+
+func (r *received) add(arg any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.args = append(r.args, arg)
+}
+
+func (r *received) get() []any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]any(nil), r.args...)
+}
+
+func TestAttach(t *testing.T) {
+	type order struct {
+		ID int
+	}
+
+	registry := eventemitter.NewRegistry()
+	registry.Register("order", order{})
+
+	broker := NewMemory()
+	defer broker.Close()
+
+	first := eventemitter.New()
+	firstOrders := &received{}
+	first.On("order", func(o order) { firstOrders.add(o) })
+
+	second := eventemitter.New()
+	secondOrders := &received{}
+	second.On("order", func(o order) { secondOrders.add(o) })
+	second.On("local", func() { secondOrders.add("local") })
+
+	a, err := Attach(first, broker, WithEvents("order"), WithRegistry(registry), WithPrefix("app."))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer a.Close()
+
+	b, err := Attach(second, broker, WithEvents("order", "local"), WithRegistry(registry), WithPrefix("app."))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Local listeners are called once, remote ones through the broker.
+	assert.NoError(t, first.EmitSync("order", order{1}))
+	assert.NoError(t, second.EmitSync("order", order{2}))
+
+	assert.Eventually(t, func() bool {
+		return len(firstOrders.get()) == 2 && len(secondOrders.get()) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	time.Sleep(20 * time.Millisecond)
+	assert.ElementsMatch(t, []any{order{1}, order{2}}, firstOrders.get())
+	assert.ElementsMatch(t, []any{order{1}, order{2}}, secondOrders.get())
+
+	// Detached emitters keep their local listeners.
+	assert.NoError(t, b.Close())
+	assert.NoError(t, first.EmitSync("order", order{4}))
+	assert.NoError(t, second.EmitSync("local"))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 3, len(firstOrders.get()))
+	assert.Equal(t, 3, len(secondOrders.get()))
+}
+
+func TestAttachClosedBroker(t *testing.T) {
+	broker := NewMemory()
+	emitter := eventemitter.New()
+
+	var errs []error
+	a, err := Attach(emitter, broker, WithEvents("event"), WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer a.Close()
+
+	// Errors of the broker are passed to the error handler.
+	assert.NoError(t, broker.Close())
+	emitter.On("event", func() {})
+	assert.NoError(t, emitter.EmitSync("event"))
+	assert.Equal(t, []error{ErrClosed}, errs)
+
+	_, err = Attach(emitter, broker, WithEvents("event"))
+	assert.Equal(t, ErrClosed, err)
+}
+
+func TestAttachListeners(t *testing.T) {
+	broker := NewMemory()
+	defer broker.Close()
+
+	first := eventemitter.New()
+	second := eventemitter.New()
+	secondEvents := &received{}
+	second.On("event", func(s string) { secondEvents.add(s) })
+
+	a, err := Attach(first, broker, WithEvents("event"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer a.Close()
+
+	b, err := Attach(second, broker, WithEvents("event"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer b.Close()
+
+	// The attachment doesn't add listeners.
+	_, err = first.ListenerCount("event")
+	assert.Equal(t, eventemitter.ErrEventNotExists, err)
+	assert.Empty(t, first.EventNames())
+
+	// Events without local listeners are published.
+	assert.Equal(t, eventemitter.ErrEventNotExists, first.EmitSync("event", "first"))
+
+	// Removing the local listeners doesn't detach the emitter.
+	first.On("event", func(s string) {})
+	first.RemoveAllListeners("event")
+	first.EmitSync("event", "second")
+
+	assert.Eventually(t, func() bool {
+		return len(secondEvents.get()) == 2
+	}, time.Second, 5*time.Millisecond)
+	assert.ElementsMatch(t, []any{"first", "second"}, secondEvents.get())
+}
+
+func TestAttachMismatchedArguments(t *testing.T) {
+	broker := NewMemory()
+	defer broker.Close()
+
+	publisher := eventemitter.New()
+	a, err := Attach(publisher, broker, WithEvents("n"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer a.Close()
+
+	subscriber := eventemitter.New()
+	subscriber.On("n", func(n int) {})
+
+	var errs []error
+	b, err := Attach(subscriber, broker, WithEvents("n"), WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer b.Close()
+
+	// The number is decoded as a float64 by the JSON codec. The subscriber
+	// reports it instead of panicking into the synchronous publication.
+	publisher.On("n", func(n int) {})
+	assert.NotPanics(t, func() {
+		assert.NoError(t, publisher.EmitSync("n", 1))
+	})
+
+	if assert.Equal(t, 1, len(errs)) {
+		assert.Contains(t, errs[0].Error(), "Emit of event n received from the broker failed")
+		assert.Contains(t, errs[0].Error(), "float64")
+	}
+}
+
+func TestMemory(t *testing.T) {
+	broker := NewMemory()
+
+	var messages []string
+	first, err := broker.Subscribe("subject", func(subject string, data []byte) {
+		messages = append(messages, "first:"+string(data))
+	})
+	assert.NoError(t, err)
+	_, err = broker.Subscribe("subject", func(subject string, data []byte) {
+		messages = append(messages, "second:"+string(data))
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, broker.Publish(context.Background(), "subject", []byte("1")))
+	assert.NoError(t, broker.Publish(context.Background(), "other", []byte("2")))
+	assert.NoError(t, first.Unsubscribe())
+	assert.NoError(t, broker.Publish(context.Background(), "subject", []byte("3")))
+	assert.Equal(t, []string{"first:1", "second:1", "second:3"}, messages)
+
+	assert.NoError(t, broker.Close())
+	assert.Equal(t, ErrClosed, broker.Publish(context.Background(), "subject", nil))
+}
+
+func TestAttachReceivedEvents(t *testing.T) {
+	broker := NewMemory()
+	defer broker.Close()
+
+	emitter := eventemitter.New()
+	events := &received{}
+	emitter.On("order", func(id string) { events.add("order:" + id) })
+	emitter.On("other", func(id string) { events.add("other:" + id) })
+
+	var errs []error
+	a, err := Attach(emitter, broker, WithEvents("order", "other"), WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer a.Close()
+
+	publish := func(subject, eventName string, arguments ...any) {
+		event, err := eventemitter.NewEventCodec(eventemitter.JSONCodec, nil).Marshal(eventName, arguments)
+		if assert.NoError(t, err) {
+			data := append(make([]byte, originSize), event...)
+			assert.NoError(t, broker.Publish(context.Background(), subject, data))
+		}
+	}
+
+	// Events published on the subject of another event are dropped.
+	publish("order", "other", "1")
+	publish("order", "order", "2")
+
+	assert.Eventually(t, func() bool {
+		return len(events.get()) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []any{"order:2"}, events.get())
+
+	// Errors of the emits are reported.
+	assert.NoError(t, emitter.Close())
+	publish("order", "order", "3")
+
+	if assert.Equal(t, 1, len(errs)) {
+		assert.ErrorIs(t, errs[0], eventemitter.ErrClosed)
+	}
+}
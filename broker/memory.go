@@ -0,0 +1,96 @@
+package broker
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory is an in-memory broker, for tests and single-process deployments.
+// Messages are delivered synchronously by Publish, in the order of the
+// subscriptions.
+type Memory struct {
+	mu            sync.RWMutex
+	subscriptions map[string][]*memorySubscription
+	closed        bool
+}
+
+type memorySubscription struct {
+	memory  *Memory
+	subject string
+	handler func(subject string, data []byte)
+}
+
+// NewMemory returns an in-memory broker.
+func NewMemory() *Memory {
+	return &Memory{subscriptions: make(map[string][]*memorySubscription)}
+}
+
+// Publish calls the handlers subscribed to the subject with a copy of the data.
+func (m *Memory) Publish(ctx context.Context, subject string, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return ErrClosed
+	}
+	subscriptions := m.subscriptions[subject]
+	m.mu.RUnlock()
+
+	for _, s := range subscriptions {
+		s.handler(subject, append([]byte(nil), data...))
+	}
+
+	return nil
+}
+
+// Subscribe calls the handler with the data published on the subject.
+func (m *Memory) Subscribe(subject string, handler func(subject string, data []byte)) (Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, ErrClosed
+	}
+
+	s := &memorySubscription{memory: m, subject: subject, handler: handler}
+
+	// The slices are copied on write, as Publish iterates them unlocked.
+	subscriptions := m.subscriptions[subject]
+	m.subscriptions[subject] = append(subscriptions[:len(subscriptions):len(subscriptions)], s)
+
+	return s, nil
+}
+
+// Close removes the subscriptions. Further publications and subscriptions
+// fail with ErrClosed.
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+	m.subscriptions = make(map[string][]*memorySubscription)
+
+	return nil
+}
+
+func (s *memorySubscription) Unsubscribe() error {
+	m := s.memory
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	subscriptions := m.subscriptions[s.subject]
+	for i, other := range subscriptions {
+		if other == s {
+			remaining := make([]*memorySubscription, 0, len(subscriptions)-1)
+			remaining = append(remaining, subscriptions[:i]...)
+			m.subscriptions[s.subject] = append(remaining, subscriptions[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,369 @@
+package eventemitter
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+var ErrCoalesceListener = errors.New("Listener of all the coalesced emits must accept a single [][]any argument")
+
+var batchType = reflect.TypeOf([][]any(nil))
+
+// Coalesce selects the arguments a debounced or throttled listener is called
+// with, when several emits are coalesced into a single call.
+type Coalesce uint8
+
+const (
+	CoalesceLast  Coalesce = iota // The arguments of the last emit.
+	CoalesceFirst                 // The arguments of the first emit.
+	CoalesceAll                   // The arguments of every emit, as a single [][]any argument.
+)
+
+// Edge selects the calls of a throttled listener within an interval.
+type Edge uint8
+
+const (
+	Leading  Edge = 1 << iota // Called on the first emit of the interval.
+	Trailing                  // Called at the end of the interval, if emitted during it.
+)
+
+// coalescer buffers the emits of a debounced, throttled or batching listener.
+// Its calls are queued, and made one at a time by a single goroutine.
+type coalescer struct {
+	mu       sync.Mutex
+	wait     time.Duration
+	throttle bool
+	edge     Edge
+	coalesce Coalesce
+	size     int // Flushes when the number of buffered emits reaches size.
+
+	ctx     context.Context // The context of the last emit.
+	pending [][]any
+	records []*durableRecord // Durable records of the buffered emits.
+	timer   *time.Timer
+	gen     uint64 // Invalidates the timers stopped too late.
+
+	queue   []coalescedCall
+	running bool // A goroutine makes the queued calls.
+}
+
+// coalescedCall is a call of a coalesced listener, acknowledging the durable
+// records of the coalesced emits once it succeeded.
+type coalescedCall struct {
+	ctx       context.Context
+	arguments []any
+	records   []*durableRecord
+}
+
+// WithDebounce delays the calls of the listener until no event was emitted for
+// the wait duration. The emits are coalesced into a single call, using the
+// arguments of the last emit, unless WithCoalesce is given.
+//
+// Coalesced listeners are called asynchronously in every emit mode, one call
+// at a time, their errors are passed to the dead letter hook, and the pending
+// calls are made on shutdown. Durable emits are acknowledged once the call
+// they are coalesced into succeeded.
+func WithDebounce(wait time.Duration) ListenerOption {
+	return func(h *handler) {
+		c := h.coalescing()
+		c.wait = wait
+		c.throttle = false
+	}
+}
+
+// WithThrottle calls the listener at most once per interval. With Leading, the
+// listener is called on the first emit of an interval; with Trailing, at the
+// end of the interval with the emits coalesced during it. Both edges are used
+// when edge is zero. See WithDebounce for the calls of coalesced listeners.
+func WithThrottle(interval time.Duration, edge Edge) ListenerOption {
+	return func(h *handler) {
+		c := h.coalescing()
+		c.wait = interval
+		c.throttle = true
+		c.edge = edge
+
+		if edge == 0 {
+			c.edge = Leading | Trailing
+		}
+	}
+}
+
+// WithCoalesce selects the arguments of debounced and throttled listeners. With
+// CoalesceAll, the listener must accept a single [][]any argument, after an
+// optional context, or AddListener returns ErrCoalesceListener.
+func WithCoalesce(coalesce Coalesce) ListenerOption {
+	return func(h *handler) {
+		h.coalescing().coalesce = coalesce
+	}
+}
+
+func (h *handler) coalescing() *coalescer {
+	if h.coalescer == nil {
+		h.coalescer = &coalescer{}
+	}
+
+	return h.coalescer
+}
+
+// accepts reports whether the listener accepts the calls of the coalescer.
+func (c *coalescer) accepts(h *handler) bool {
+	if c.coalesce != CoalesceAll {
+		return true
+	}
+
+	offset := 0
+	if h.context {
+		offset = 1
+	}
+
+	return !h.fnType.IsVariadic() && h.fnType.NumIn() == offset+1 && h.fnType.In(offset) == batchType
+}
+
+// push buffers the arguments of an emit. Must be called with c.mu held.
+func (c *coalescer) push(arguments []any, record *durableRecord) {
+	arguments = append([]any(nil), arguments...)
+
+	switch {
+	case c.coalesce == CoalesceAll || len(c.pending) == 0:
+		c.pending = append(c.pending, arguments)
+	case c.coalesce == CoalesceLast:
+		c.pending[0] = arguments
+	}
+
+	if record != nil {
+		c.records = append(c.records, record)
+	}
+}
+
+// take returns the call of the buffered emits, and empties the buffer. Must be
+// called with c.mu held.
+func (c *coalescer) take() (coalescedCall, bool) {
+	pending, records := c.pending, c.records
+	c.pending, c.records = nil, nil
+
+	if len(pending) == 0 {
+		return coalescedCall{}, false
+	}
+
+	call := coalescedCall{ctx: c.ctx, arguments: pending[0], records: records}
+	if c.coalesce == CoalesceAll {
+		call.arguments = []any{pending}
+	}
+
+	return call, true
+}
+
+// schedule starts the timer of the coalescer. Must be called with c.mu held.
+func (c *coalescer) schedule(e *Emitter, eventName string, listener *handler) {
+	c.stop()
+
+	gen := c.gen
+	c.timer = time.AfterFunc(c.wait, func() {
+		e.fire(eventName, listener, gen)
+	})
+}
+
+// stop stops the timer of the coalescer. Must be called with c.mu held.
+func (c *coalescer) stop() {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+
+	c.gen++
+}
+
+// coalesce buffers the emit for the coalesced listener. Panics if the
+// arguments don't match the listener, as the other emit modes. Returns the
+// error of the acknowledgement of a durable emit dropped by a throttle.
+func (e *Emitter) coalesce(ctx context.Context, eventName string, listener *handler, arguments []any, record *durableRecord) error {
+	c := listener.coalescer
+	if c.coalesce != CoalesceAll {
+		e.prepare(eventName, listener, arguments, nil)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ctx = ctx
+
+	if c.throttle {
+		if c.timer == nil {
+			c.schedule(e, eventName, listener)
+
+			if c.edge&Leading != 0 {
+				c.push(arguments, record)
+				call, _ := c.take()
+				e.deliverLater(eventName, listener, call)
+				return nil
+			}
+		}
+
+		if c.edge&Trailing == 0 {
+			// The emits dropped by the throttle are acknowledged.
+			return e.ack(record, listener)
+		}
+
+		c.push(arguments, record)
+
+		return nil
+	}
+
+	c.push(arguments, record)
+
+	if c.size > 0 && len(c.pending) >= c.size {
+		c.stop()
+		call, _ := c.take()
+		e.deliverLater(eventName, listener, call)
+		return nil
+	}
+
+	// Debounced listeners wait from the last emit, batches from the first.
+	if c.wait > 0 && (c.timer == nil || c.size == 0) {
+		c.schedule(e, eventName, listener)
+	}
+
+	return nil
+}
+
+// fire calls the coalesced listener when its timer expires.
+func (e *Emitter) fire(eventName string, listener *handler, gen uint64) {
+	c := listener.coalescer
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if gen != c.gen {
+		return
+	}
+
+	c.timer = nil
+
+	call, ok := c.take()
+	if !ok {
+		return
+	}
+
+	// A throttled listener starts a new interval when it is called.
+	if c.throttle {
+		c.schedule(e, eventName, listener)
+	}
+
+	e.deliverLater(eventName, listener, call)
+}
+
+// flushCoalesced makes the pending calls of the coalesced listeners. Called on
+// shutdown, after the emitter is closed.
+func (e *Emitter) flushCoalesced() {
+	e.listeners.Range(func(eventName, listeners any) bool {
+		for _, listener := range listeners.([]*handler) {
+			c := listener.coalescer
+			if c == nil {
+				continue
+			}
+
+			c.mu.Lock()
+			c.stop()
+			if call, ok := c.take(); ok {
+				e.deliverLater(eventName.(string), listener, call)
+			}
+			c.mu.Unlock()
+		}
+
+		return true
+	})
+
+	// The calls taken by the timers until now are made, later ones are
+	// dropped, as Shutdown waits for the in-flight calls.
+	e.state.Lock()
+	e.drained = true
+	e.state.Unlock()
+}
+
+// deliverLater queues the call of the coalesced listener, and starts the
+// goroutine making the queued calls if needed. The calls are made after the
+// emitter is closed, until Shutdown flushed the coalesced listeners. Must be
+// called with c.mu held.
+func (e *Emitter) deliverLater(eventName string, listener *handler, call coalescedCall) {
+	c := listener.coalescer
+	c.queue = append(c.queue, call)
+
+	if c.running {
+		return
+	}
+
+	e.state.RLock()
+	drained := e.drained
+	if !drained {
+		e.inflight.Add(1)
+	}
+	e.state.RUnlock()
+
+	if drained {
+		c.queue = nil
+		e.dropped(eventName, ErrClosed)
+		return
+	}
+
+	c.running = true
+	go e.drainCoalesced(eventName, listener)
+}
+
+// drainCoalesced makes the queued calls of the coalesced listener, in order,
+// until the queue is empty.
+func (e *Emitter) drainCoalesced(eventName string, listener *handler) {
+	defer e.inflight.Done()
+
+	c := listener.coalescer
+	for {
+		c.mu.Lock()
+		if len(c.queue) == 0 {
+			c.running = false
+			c.mu.Unlock()
+			return
+		}
+
+		call := c.queue[0]
+		c.queue[0] = coalescedCall{}
+		c.queue = c.queue[1:]
+		c.mu.Unlock()
+
+		e.callCoalesced(eventName, listener, call)
+	}
+}
+
+// callCoalesced calls the coalesced listener, and acknowledges the durable
+// records of the call if it succeeded. Panics are passed to the dead letter
+// hook as a *PanicError.
+func (e *Emitter) callCoalesced(eventName string, listener *handler, call coalescedCall) {
+	if e.metrics != nil {
+		e.metrics.AsyncStarted(eventName)
+		defer e.metrics.AsyncFinished(eventName)
+	}
+
+	inv := invocation{eventName: eventName, listener: listener, arguments: call.arguments}
+	defer func() {
+		if r := recover(); r != nil {
+			e.deadLettered(inv, 1, &PanicError{Event: eventName, Value: r, Stack: debug.Stack()})
+		}
+	}()
+
+	inv, _ = e.prepare(eventName, listener, call.arguments, nil)
+
+	attempts, err := e.deliver(call.ctx, inv, true)
+	for _, record := range call.records {
+		if err != nil {
+			break
+		}
+
+		err = e.ack(record, listener)
+	}
+
+	if err != nil {
+		e.deadLettered(inv, attempts, err)
+	}
+}
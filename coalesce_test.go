@@ -0,0 +1,289 @@
+package eventemitter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// calls records the calls of a listener.
+type calls struct {
+	mu   sync.Mutex
+	args []any
+}
+
+func (c *calls) add(arg any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.args = append(c.args, arg)
+}
+
+func (c *calls) get() []any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]any(nil), c.args...)
+}
+
+func TestDebounce(t *testing.T) {
+	emitter := New()
+
+	received := &calls{}
+	emitter.On("event", func(a int) {
+		received.add(a)
+	}, WithDebounce(50*time.Millisecond))
+
+	for i := 1; i <= 5; i++ {
+		assert.NoError(t, emitter.EmitSync("event", i))
+	}
+	assert.Empty(t, received.get())
+
+	assert.Eventually(t, func() bool {
+		return len(received.get()) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []any{5}, received.get())
+
+	// A new burst after the wait duration.
+	emitter.Emit("event", 6)
+	assert.Eventually(t, func() bool {
+		return len(received.get()) == 2
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []any{5, 6}, received.get())
+}
+
+func TestDebounceCoalesce(t *testing.T) {
+	emitter := New()
+
+	first := &calls{}
+	emitter.On("event", func(a int, b string) {
+		first.add(a)
+	}, WithDebounce(time.Hour), WithCoalesce(CoalesceFirst))
+
+	all := &calls{}
+	emitter.On("event", func(batch [][]any) {
+		all.add(batch)
+	}, WithDebounce(time.Hour), WithCoalesce(CoalesceAll))
+
+	emitter.EmitSync("event", 1, "a")
+	emitter.EmitSync("event", 2, "b")
+
+	// Pending calls are made on shutdown.
+	assert.NoError(t, emitter.Close())
+	assert.Equal(t, []any{1}, first.get())
+	assert.Equal(t, []any{[][]any{{1, "a"}, {2, "b"}}}, all.get())
+}
+
+func TestThrottle(t *testing.T) {
+	emitter := New()
+
+	leading := &calls{}
+	emitter.On("event", func(a int) {
+		leading.add(a)
+	}, WithThrottle(time.Hour, Leading))
+
+	trailing := &calls{}
+	emitter.On("event", func(a int) {
+		trailing.add(a)
+	}, WithThrottle(time.Hour, Trailing))
+
+	both := &calls{}
+	emitter.On("event", func(a int) {
+		both.add(a)
+	}, WithThrottle(time.Hour, 0))
+
+	for i := 1; i <= 3; i++ {
+		emitter.EmitSync("event", i)
+	}
+
+	assert.Eventually(t, func() bool {
+		return len(leading.get()) == 1 && len(both.get()) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Empty(t, trailing.get())
+
+	assert.NoError(t, emitter.Close())
+	assert.Equal(t, []any{1}, leading.get())
+	assert.Equal(t, []any{3}, trailing.get())
+	assert.Equal(t, []any{1, 3}, both.get())
+}
+
+func TestThrottleInterval(t *testing.T) {
+	emitter := New()
+
+	received := &calls{}
+	emitter.On("event", func(a int) {
+		received.add(a)
+	}, WithThrottle(50*time.Millisecond, Leading|Trailing))
+
+	emitter.EmitSync("event", 1)
+	emitter.EmitSync("event", 2)
+
+	assert.Eventually(t, func() bool {
+		return len(received.get()) == 2
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []any{1, 2}, received.get())
+
+	// The trailing call starts a new interval, which ends without calls.
+	time.Sleep(100 * time.Millisecond)
+	emitter.EmitSync("event", 3)
+	assert.Eventually(t, func() bool {
+		return len(received.get()) == 3
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []any{1, 2, 3}, received.get())
+
+	assert.NoError(t, emitter.Close())
+}
+
+func TestCoalescedCallsAreSerial(t *testing.T) {
+	emitter := New()
+
+	var mu sync.Mutex
+	running, peak := 0, 0
+	received := &calls{}
+	emitter.On("event", func(a int) {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+		received.add(a)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+	}, WithDebounce(time.Millisecond))
+
+	// The timer fires several times while the listener is running.
+	for i := 1; i <= 10; i++ {
+		emitter.EmitSync("event", i)
+		time.Sleep(3 * time.Millisecond)
+	}
+
+	assert.NoError(t, emitter.Close())
+	assert.Equal(t, 1, peak)
+
+	// The calls are made in order, the last one with the last emit.
+	var args []int
+	for _, arg := range received.get() {
+		args = append(args, arg.(int))
+	}
+	if assert.NotEmpty(t, args) {
+		assert.IsIncreasing(t, args)
+		assert.Equal(t, 10, args[len(args)-1])
+	}
+}
+
+func TestCoalescedCallOnShutdown(t *testing.T) {
+	emitter := New()
+
+	received := &calls{}
+	emitter.On("event", func(a int) {
+		received.add(a)
+	}, WithDebounce(time.Hour))
+	emitter.EmitSync("event", 1)
+
+	// The timer fires after the emitter is closed, before the pending calls
+	// are flushed.
+	listeners, _ := emitter.getListeners("event")
+	c := listeners[0].coalescer
+
+	emitter.state.Lock()
+	emitter.closed = true
+	emitter.state.Unlock()
+
+	c.mu.Lock()
+	gen := c.gen
+	c.mu.Unlock()
+	emitter.fire("event", listeners[0], gen)
+
+	assert.NoError(t, emitter.Close())
+	assert.Equal(t, []any{1}, received.get())
+}
+
+func TestCoalescedDurable(t *testing.T) {
+	store := newTestStore()
+	emitter := New(WithStore(store, "event"))
+
+	fail := true
+	received := &calls{}
+	emitter.On("event", func(a int) error {
+		received.add(a)
+		if fail {
+			return errors.New("listener")
+		}
+
+		return nil
+	}, WithDebounce(time.Hour), WithListenerID("listener"))
+
+	pending := func() int {
+		records, _ := store.Pending()
+		return len(records)
+	}
+
+	// The emits are not acknowledged while buffered, nor by a failed call.
+	emitter.EmitSync("event", 1)
+	emitter.EmitSync("event", 2)
+	assert.Equal(t, 2, pending())
+
+	assert.NoError(t, emitter.Close())
+	assert.Equal(t, []any{2}, received.get())
+	assert.Equal(t, 2, pending())
+
+	// The coalesced emits are acknowledged once the call succeeded.
+	fail = false
+	emitter = New(WithStore(store, "event"))
+	emitter.On("event", func(batch [][]any) {
+		received.add(len(batch))
+	}, WithDebounce(time.Hour), WithCoalesce(CoalesceAll), WithListenerID("listener"))
+
+	assert.NoError(t, emitter.ReplayPending())
+	emitter.EmitSync("event", 3)
+	assert.Equal(t, 3, pending())
+
+	assert.NoError(t, emitter.Close())
+	assert.Equal(t, []any{2, 3}, received.get())
+	assert.Equal(t, 0, pending())
+}
+
+func TestCoalescedArguments(t *testing.T) {
+	letters := make(chan DeadLetter, 1)
+	emitter := New(WithDeadLetter(func(letter DeadLetter) {
+		letters <- letter
+	}))
+
+	// Arguments are checked by the emit.
+	emitter.On("event", func(a int) {}, WithDebounce(time.Millisecond))
+	assert.PanicsWithError(t, createTypeErr("event", 1, "int", "string"), func() {
+		emitter.EmitSync("event", "test")
+	})
+
+	// Listeners of all the coalesced emits must accept them.
+	assert.Equal(t, ErrCoalesceListener, emitter.On("all", func(a int) {}, WithDebounce(time.Millisecond), WithCoalesce(CoalesceAll)))
+	assert.NoError(t, emitter.On("all", func(ctx context.Context, batch [][]any) {}, WithDebounce(time.Millisecond), WithCoalesce(CoalesceAll)))
+
+	// Panics are passed to the dead letter hook.
+	emitter.On("panic", func(a int) {
+		panic("listener")
+	}, WithThrottle(time.Hour, Leading))
+	emitter.EmitSync("panic", 1)
+
+	select {
+	case letter := <-letters:
+		var panicErr *PanicError
+		if assert.ErrorAs(t, letter.Err, &panicErr) {
+			assert.Equal(t, "listener", panicErr.Value)
+		}
+		assert.Equal(t, []any{1}, letter.Arguments)
+	case <-time.After(time.Second):
+		assert.Fail(t, "panic not dead lettered")
+	}
+
+	assert.NoError(t, emitter.Close())
+}
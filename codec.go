@@ -0,0 +1,249 @@
+package eventemitter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+)
+
+var ErrMalformedEvent = errors.New("Malformed encoded event")
+
+var anyType = reflect.TypeOf((*any)(nil)).Elem()
+
+// Codec serializes values.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec encodes the values as JSON.
+var JSONCodec Codec = jsonCodec{}
+
+// GobCodec encodes the values with encoding/gob. The types of the arguments
+// of unregistered events, other than the basic types, must be registered with
+// gob.Register.
+var GobCodec Codec = gobCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// Registry maps event names to the types of their arguments, so encoded
+// events are decoded into arguments of the same types.
+type Registry struct {
+	mu    sync.RWMutex
+	types map[string][]reflect.Type
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{types: make(map[string][]reflect.Type)}
+}
+
+// Register sets the types of the arguments of the event, given by sample
+// values, e.g. Register("order.created", Order{}, ""). A reflect.Type is used
+// as is, e.g. for interface types.
+func (r *Registry) Register(eventName string, arguments ...any) {
+	types := make([]reflect.Type, 0, len(arguments))
+	for _, arg := range arguments {
+		if t, ok := arg.(reflect.Type); ok {
+			types = append(types, t)
+		} else if arg == nil {
+			types = append(types, anyType)
+		} else {
+			types = append(types, reflect.TypeOf(arg))
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.types[eventName] = types
+}
+
+// Types returns the types of the arguments of the event, or nil if the event
+// is not registered.
+func (r *Registry) Types(eventName string) []reflect.Type {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.types[eventName]
+}
+
+// EventCodec encodes events, with the arguments encoded by a codec. The
+// arguments of registered events are decoded into their registered types, the
+// others as the codec decodes them into an any, e.g. numbers as float64 with
+// JSON. Both ends must use the same codec and registrations.
+type EventCodec struct {
+	codec    Codec
+	registry *Registry
+}
+
+// NewEventCodec returns an event codec using the codec and the registry. The
+// registry is optional.
+func NewEventCodec(codec Codec, registry *Registry) *EventCodec {
+	return &EventCodec{codec: codec, registry: registry}
+}
+
+// Marshal encodes the event. Returns an error if an argument doesn't match its
+// registered type.
+func (c *EventCodec) Marshal(eventName string, arguments []any) ([]byte, error) {
+	types := c.registry.Types(eventName)
+
+	buf := appendUvarint(nil, uint64(len(eventName)))
+	buf = append(buf, eventName...)
+	buf = appendUvarint(buf, uint64(len(arguments)))
+
+	for i, arg := range arguments {
+		t := anyType
+		if i < len(types) {
+			t = types[i]
+		}
+
+		// Nil arguments are encoded as a zero length.
+		if arg == nil {
+			if !isNilable(t) {
+				return nil, &argsNilError{eventName, i + 1, t}
+			}
+
+			buf = appendUvarint(buf, 0)
+			continue
+		}
+
+		argType := reflect.TypeOf(arg)
+		if !argType.AssignableTo(t) {
+			return nil, &argsTypeError{eventName, i + 1, t, argType}
+		}
+
+		// The argument is encoded as a value of its registered type, so
+		// arguments of interface types are encoded as interfaces.
+		value := reflect.New(t)
+		value.Elem().Set(reflect.ValueOf(arg))
+
+		data, err := c.codec.Marshal(value.Interface())
+		if err != nil {
+			return nil, err
+		}
+
+		buf = appendUvarint(buf, uint64(len(data))+1)
+		buf = append(buf, data...)
+	}
+
+	return buf, nil
+}
+
+// Unmarshal decodes the event. The arguments are typed values, ready to be
+// passed to a listener.
+func (c *EventCodec) Unmarshal(data []byte) (eventName string, arguments []reflect.Value, err error) {
+	name, data, ok := readBytes(data, 0)
+	if !ok {
+		return "", nil, ErrMalformedEvent
+	}
+	eventName = string(name)
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count > uint64(len(data)) {
+		return "", nil, ErrMalformedEvent
+	}
+	data = data[n:]
+
+	types := c.registry.Types(eventName)
+	arguments = make([]reflect.Value, 0, count)
+
+	for i := 0; i < int(count); i++ {
+		t := anyType
+		if i < len(types) {
+			t = types[i]
+		}
+
+		var arg []byte
+		if arg, data, ok = readBytes(data, 1); !ok {
+			return "", nil, ErrMalformedEvent
+		}
+
+		if arg == nil {
+			arguments = append(arguments, reflect.Zero(t))
+			continue
+		}
+
+		value := reflect.New(t)
+		if err := c.codec.Unmarshal(arg, value.Interface()); err != nil {
+			return "", nil, err
+		}
+
+		arguments = append(arguments, value.Elem())
+	}
+
+	return eventName, arguments, nil
+}
+
+// Decode decodes the event, with the arguments ready to be emitted.
+func (c *EventCodec) Decode(data []byte) (eventName string, arguments []any, err error) {
+	eventName, values, err := c.Unmarshal(data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	arguments = make([]any, len(values))
+	for i, value := range values {
+		arguments[i] = value.Interface()
+	}
+
+	return eventName, arguments, nil
+}
+
+// readBytes reads a length prefixed byte slice, whose length is offset by
+// offset. Returns nil for a zero length with an offset.
+func readBytes(data []byte, offset uint64) ([]byte, []byte, bool) {
+	size, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, nil, false
+	}
+	data = data[n:]
+
+	if offset != 0 && size == 0 {
+		return nil, data, true
+	}
+
+	if size -= offset; size > uint64(len(data)) {
+		return nil, nil, false
+	}
+
+	return data[:size:size], data[size:], true
+}
+
+func appendUvarint(buf []byte, x uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], x)
+
+	return append(buf, tmp[:n]...)
+}
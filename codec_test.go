@@ -0,0 +1,126 @@
+package eventemitter
+
+import (
+	"encoding/gob"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type codecOrder struct {
+	ID    int
+	Items []string
+}
+
+type codecShape interface {
+	Area() float64
+}
+
+type codecSquare struct {
+	Side float64
+}
+
+func (s codecSquare) Area() float64 {
+	return s.Side * s.Side
+}
+
+func TestEventCodec(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("order", codecOrder{}, 0, (*codecOrder)(nil), []byte(nil))
+
+	for _, codec := range []Codec{JSONCodec, GobCodec} {
+		c := NewEventCodec(codec, registry)
+
+		arguments := []any{codecOrder{ID: 1, Items: []string{"a"}}, 2, &codecOrder{ID: 3}, nil}
+		data, err := c.Marshal("order", arguments)
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		eventName, values, err := c.Unmarshal(data)
+		assert.NoError(t, err)
+		assert.Equal(t, "order", eventName)
+		if assert.Equal(t, 4, len(values)) {
+			assert.Equal(t, reflect.TypeOf(codecOrder{}), values[0].Type())
+			assert.Equal(t, arguments[0], values[0].Interface())
+			assert.Equal(t, 2, values[1].Interface())
+			assert.Equal(t, &codecOrder{ID: 3}, values[2].Interface())
+			assert.Equal(t, []byte(nil), values[3].Interface())
+		}
+
+		// The decoded arguments can be emitted to typed listeners.
+		eventName, decoded, err := c.Decode(data)
+		assert.NoError(t, err)
+
+		emitter := New()
+		called := false
+		emitter.On(eventName, func(o codecOrder, n int, p *codecOrder, b []byte) {
+			called = o.ID == 1 && n == 2 && p.ID == 3 && b == nil
+		})
+		assert.NoError(t, emitter.EmitSync(eventName, decoded...))
+		assert.True(t, called)
+	}
+}
+
+func TestEventCodecUnregistered(t *testing.T) {
+	// Numbers are decoded as float64 by JSON.
+	c := NewEventCodec(JSONCodec, nil)
+	data, err := c.Marshal("event", []any{"test", 1, nil})
+	assert.NoError(t, err)
+
+	eventName, arguments, err := c.Decode(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "event", eventName)
+	assert.Equal(t, []any{"test", float64(1), nil}, arguments)
+
+	// Gob keeps the types registered with gob.
+	gob.Register(codecSquare{})
+	c = NewEventCodec(GobCodec, nil)
+	data, err = c.Marshal("event", []any{"test", 1, codecSquare{2}})
+	assert.NoError(t, err)
+
+	_, arguments, err = c.Decode(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"test", 1, codecSquare{2}}, arguments)
+}
+
+func TestEventCodecInterface(t *testing.T) {
+	gob.Register(codecSquare{})
+
+	registry := NewRegistry()
+	registry.Register("shape", reflect.TypeOf((*codecShape)(nil)).Elem())
+
+	c := NewEventCodec(GobCodec, registry)
+	data, err := c.Marshal("shape", []any{codecSquare{3}})
+	assert.NoError(t, err)
+
+	_, values, err := c.Unmarshal(data)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(values)) {
+		assert.Equal(t, reflect.Interface, values[0].Kind())
+		assert.Equal(t, float64(9), values[0].Interface().(codecShape).Area())
+	}
+}
+
+func TestEventCodecErrors(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("order", codecOrder{})
+
+	c := NewEventCodec(JSONCodec, registry)
+
+	_, err := c.Marshal("order", []any{1})
+	assert.EqualError(t, err, "Wrong argument type. Event order expected argument 1 to be eventemitter.codecOrder, got int.")
+
+	_, err = c.Marshal("order", []any{nil})
+	assert.EqualError(t, err, "Wrong argument type. Event order expected argument 1 to be eventemitter.codecOrder, got nil.")
+
+	data, _ := c.Marshal("order", []any{codecOrder{ID: 1}})
+	for i := 0; i < len(data); i++ {
+		_, _, err := c.Unmarshal(data[:i])
+		assert.Error(t, err)
+	}
+
+	_, _, err = c.Unmarshal([]byte{0xff})
+	assert.Equal(t, ErrMalformedEvent, err)
+}
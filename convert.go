@@ -0,0 +1,73 @@
+package eventemitter
+
+import "reflect"
+
+// WithArgumentConversion enables the conversion of arguments that are not
+// assignable to the parameters of a listener, but can be converted without
+// losing information:
+//   - values of named types to types with the same underlying type, e.g.
+//     string to type MyString string, and back,
+//   - integers to wider integers, e.g. int32 to int64, or uint8 to int16,
+//   - integers to floats that represent them exactly, e.g. int32 to float64,
+//   - float32 to float64, and complex64 to complex128.
+func WithArgumentConversion() Option {
+	return func(e *Emitter) {
+		e.convert = true
+	}
+}
+
+// canConvert reports whether a value of type from can be converted to type to
+// without losing information.
+func (e *Emitter) canConvert(from, to reflect.Type) bool {
+	if !from.ConvertibleTo(to) {
+		return false
+	}
+
+	// Types of the same kind are only convertible if their underlying types
+	// are identical.
+	if from.Kind() == to.Kind() {
+		return true
+	}
+
+	switch {
+	case e.isSigned(from) && e.isSigned(to), e.isUnsigned(from) && e.isUnsigned(to):
+		return to.Bits() >= from.Bits()
+	case e.isUnsigned(from) && e.isSigned(to):
+		return to.Bits() > from.Bits()
+	case (e.isSigned(from) || e.isUnsigned(from)) && e.isFloat(to):
+		// Integers are exact up to the size of the significand.
+		if to.Kind() == reflect.Float32 {
+			return from.Bits() <= 24
+		}
+
+		return from.Bits() <= 53
+	case from.Kind() == reflect.Float32 && to.Kind() == reflect.Float64:
+		return true
+	case from.Kind() == reflect.Complex64 && to.Kind() == reflect.Complex128:
+		return true
+	}
+
+	return false
+}
+
+func (e *Emitter) isSigned(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+
+	return false
+}
+
+func (e *Emitter) isUnsigned(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+
+	return false
+}
+
+func (e *Emitter) isFloat(t reflect.Type) bool {
+	return t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64
+}
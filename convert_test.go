@@ -0,0 +1,90 @@
+package eventemitter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testString string
+
+type testInt int
+
+func TestArgumentConversion(t *testing.T) {
+	emitter := New(WithArgumentConversion())
+
+	// Numeric widening.
+	emitter.On("int64", func(a int64, b float64, c ...int32) {
+		assert.Equal(t, int64(1), a)
+		assert.Equal(t, float64(2), b)
+		assert.Equal(t, []int32{3, 4}, c)
+	})
+	assert.NotPanics(t, func() { emitter.EmitSync("int64", 1, int32(2), int8(3), uint16(4)) })
+
+	// Named types sharing an underlying type.
+	emitter.On("named", func(a testString, b string, c testInt) {
+		assert.Equal(t, testString("test"), a)
+		assert.Equal(t, "named", b)
+		assert.Equal(t, testInt(42), c)
+	})
+	emitter.On("named", func(a string, b testString, c int) {
+		assert.Equal(t, "test", a)
+	})
+	assert.NotPanics(t, func() { emitter.EmitSync("named", "test", testString("named"), 42) })
+
+	// Lossy conversions.
+	emitter.On("lossy", func(a int32) {})
+	assert.PanicsWithError(t, createTypeErr("lossy", 1, "int32", "int"), func() { emitter.EmitSync("lossy", 1) })
+	assert.PanicsWithError(t, createTypeErr("lossy", 1, "int32", "float64"), func() { emitter.EmitSync("lossy", 1.0) })
+	assert.PanicsWithError(t, createTypeErr("lossy", 1, "int32", "uint32"), func() { emitter.EmitSync("lossy", uint32(1)) })
+
+	emitter.On("string", func(a string) {})
+	assert.PanicsWithError(t, createTypeErr("string", 1, "string", "int"), func() { emitter.EmitSync("string", 65) })
+	assert.PanicsWithError(t, createTypeErr("string", 1, "string", "[]uint8"), func() { emitter.EmitSync("string", []byte("test")) })
+
+	// Conversion is disabled by default.
+	emitter = New()
+	emitter.On("int64", func(a int64) {})
+	assert.PanicsWithError(t, createTypeErr("int64", 1, "int64", "int"), func() { emitter.EmitSync("int64", 1) })
+}
+
+func TestCanConvert(t *testing.T) {
+	emitter := New()
+
+	type named struct{ a int }
+	type other struct{ a int }
+
+	for _, c := range []struct {
+		from, to any
+		ok       bool
+	}{
+		{int8(0), int16(0), true},
+		{int32(0), int(0), true},
+		{int(0), int64(0), true},
+		{int64(0), int32(0), false},
+		{uint8(0), uint64(0), true},
+		{uint8(0), int16(0), true},
+		{uint16(0), int16(0), false},
+		{int8(0), uint16(0), false},
+		{int16(0), float32(0), true},
+		{int32(0), float32(0), false},
+		{uint32(0), float64(0), true},
+		{int64(0), float64(0), false},
+		{float32(0), float64(0), true},
+		{float64(0), float32(0), false},
+		{float64(0), int64(0), false},
+		{complex64(0), complex128(0), true},
+		{"", testString(""), true},
+		{testInt(0), 0, true},
+		{testInt(0), int64(0), true},
+		{named{}, other{}, true},
+		{&named{}, &other{}, true},
+		{0, "", false},
+		{[]byte{}, "", false},
+		{"", []byte{}, false},
+	} {
+		from, to := reflect.TypeOf(c.from), reflect.TypeOf(c.to)
+		assert.Equal(t, c.ok, emitter.canConvert(from, to), "%s to %s", from, to)
+	}
+}
@@ -0,0 +1,100 @@
+package eventemitter
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime"
+)
+
+var ErrMaxDepthExceeded = errors.New("Maximum emit depth exceeded")
+
+// unknownEvent names the nested events not tracked by the context.
+const unknownEvent = "?"
+
+// chainKey is the context key of the chain of nested emits.
+type chainKey struct{}
+
+// emitFunc is the name of the function of the emits on the call stack.
+var emitFunc string
+
+func init() {
+	emitFunc = runtime.FuncForPC(reflect.ValueOf((*Emitter).emit).Pointer()).Name()
+}
+
+// WithMaxDepth limits the depth of nested emits, e.g. listeners emitting their
+// own event, to depth. Emits exceeding the depth return a *DepthError with the
+// chain of the nested events, without calling the listeners.
+//
+// Nested emits are tracked through the context passed to the listeners, in
+// every emit mode: listeners accepting a context.Context and emitting with it,
+// e.g. EmitSyncContext(ctx, "event"), have their emits tracked. Emits of
+// listeners not using the context are tracked through the call stack, if the
+// listeners are called in the emitting goroutine, as by EmitSync; their events
+// are named "?" in the chain.
+func WithMaxDepth(depth int) Option {
+	return func(e *Emitter) {
+		e.maxDepth = depth
+	}
+}
+
+// WithDepthPanic makes the emits exceeding the maximum depth panic with the
+// *DepthError, instead of returning it.
+func WithDepthPanic() Option {
+	return func(e *Emitter) {
+		e.depthPanic = true
+	}
+}
+
+// enter adds the emit to the chain of nested emits carried by the context.
+// Returns the context of the listeners, carrying the chain.
+func (e *Emitter) enter(ctx context.Context, eventName string) (context.Context, error) {
+	outer, _ := ctx.Value(chainKey{}).([]string)
+	if untracked := stackDepth() - len(outer); untracked > 0 {
+		unknown := make([]string, untracked, untracked+len(outer))
+		for i := range unknown {
+			unknown[i] = unknownEvent
+		}
+		outer = append(unknown, outer...)
+	}
+
+	chain := append(outer[:len(outer):len(outer)], eventName)
+
+	if len(chain) > e.maxDepth {
+		err := &DepthError{MaxDepth: e.maxDepth, Chain: chain}
+		if e.depthPanic {
+			panic(err)
+		}
+
+		return ctx, err
+	}
+
+	return context.WithValue(ctx, chainKey{}, chain), nil
+}
+
+// stackDepth returns the number of emits on the call stack of the goroutine,
+// the current one excluded.
+func stackDepth() int {
+	pcs := make([]uintptr, 64)
+	for {
+		// Skip runtime.Callers, stackDepth, enter, and the current emit.
+		n := runtime.Callers(4, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, 2*len(pcs))
+	}
+
+	depth := 0
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function == emitFunc {
+			depth++
+		}
+		if !more {
+			return depth
+		}
+	}
+}
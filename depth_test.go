@@ -0,0 +1,145 @@
+package eventemitter
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxDepth(t *testing.T) {
+	emitter := New(WithMaxDepth(3))
+
+	var errs []error
+	emitter.On("ping", func(ctx context.Context) {
+		errs = append(errs, emitter.EmitSyncContext(ctx, "pong"))
+	})
+	emitter.On("pong", func(ctx context.Context) {
+		errs = append(errs, emitter.EmitSyncContext(ctx, "ping"))
+	})
+
+	assert.NoError(t, emitter.EmitSync("ping"))
+	if assert.Equal(t, 3, len(errs)) {
+		var depthErr *DepthError
+		assert.True(t, errors.As(errs[0], &depthErr))
+		assert.True(t, errors.Is(errs[0], ErrMaxDepthExceeded))
+		assert.Equal(t, []string{"ping", "pong", "ping", "pong"}, depthErr.Chain)
+		assert.Equal(t, "Maximum emit depth of 3 exceeded: ping -> pong -> ping -> pong", errs[0].Error())
+		assert.NoError(t, errs[1])
+		assert.NoError(t, errs[2])
+	}
+
+	// The depth is reset after the emit.
+	errs = nil
+	assert.NoError(t, emitter.EmitSync("pong"))
+	assert.Equal(t, 3, len(errs))
+	assert.Error(t, errs[0])
+}
+
+func TestMaxDepthStack(t *testing.T) {
+	emitter := New(WithMaxDepth(5))
+
+	// Listeners not using the context are tracked through the call stack.
+	var errs []error
+	emitter.On("event", func() {
+		errs = append(errs, emitter.EmitSync("event"))
+	})
+
+	assert.NoError(t, emitter.EmitSync("event"))
+	if assert.Equal(t, 5, len(errs)) {
+		var depthErr *DepthError
+		if assert.True(t, errors.As(errs[0], &depthErr)) {
+			assert.Equal(t, []string{"?", "?", "?", "?", "?", "event"}, depthErr.Chain)
+		}
+	}
+
+	// Emits tracked through the context, nested in untracked ones.
+	errs = nil
+	emitter.On("ping", func() {
+		errs = append(errs, emitter.EmitSync("pong"))
+	})
+	emitter.On("pong", func(ctx context.Context) {
+		errs = append(errs, emitter.EmitSyncContext(ctx, "ping"))
+	})
+
+	assert.NoError(t, emitter.EmitSync("ping"))
+	if assert.Equal(t, 5, len(errs)) {
+		assert.True(t, errors.Is(errs[0], ErrMaxDepthExceeded))
+	}
+}
+
+func TestMaxDepthPanic(t *testing.T) {
+	emitter := New(WithMaxDepth(1), WithDepthPanic())
+
+	emitter.On("event", func(ctx context.Context) {
+		emitter.EmitSyncContext(ctx, "event")
+	})
+
+	assert.PanicsWithError(t, "Maximum emit depth of 1 exceeded: event -> event", func() {
+		emitter.EmitSync("event")
+	})
+}
+
+func TestMaxDepthContext(t *testing.T) {
+	emitter := New(WithMaxDepth(5))
+
+	done := make(chan error, 1)
+	emitter.On("event", func(ctx context.Context) {
+		if err := emitter.EmitContext(ctx, "event"); err != nil {
+			done <- err
+		}
+	})
+
+	assert.NoError(t, emitter.Emit("event"))
+
+	select {
+	case err := <-done:
+		var depthErr *DepthError
+		if assert.True(t, errors.As(err, &depthErr)) {
+			assert.Equal(t, 6, len(depthErr.Chain))
+		}
+	case <-time.After(time.Second):
+		assert.Fail(t, "async emits not tracked")
+	}
+
+	assert.NoError(t, emitter.Close())
+}
+
+func TestMaxDepthTimeout(t *testing.T) {
+	emitter := New(WithMaxDepth(3), WithListenerTimeout(time.Second))
+
+	// The listeners are called in other goroutines.
+	var calls int32
+	emitter.On("event", func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return emitter.EmitSyncContext(ctx, "event")
+	})
+
+	err := emitter.EmitSync("event")
+	assert.True(t, errors.Is(err, ErrMaxDepthExceeded))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.NoError(t, emitter.Close())
+}
+
+func TestMaxDepthParallel(t *testing.T) {
+	emitter := New(WithMaxDepth(3))
+
+	var calls int32
+	emitter.On("event", func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return emitter.EmitParallelContext(ctx, "event")
+	})
+	emitter.On("event", func(ctx context.Context) error {
+		return nil
+	})
+
+	err := emitter.EmitParallel("event")
+	var depthErr *DepthError
+	if assert.True(t, errors.As(err, &depthErr)) {
+		assert.Equal(t, []string{"event", "event", "event", "event"}, depthErr.Chain)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
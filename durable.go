@@ -0,0 +1,188 @@
+package eventemitter
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// Store persists durable events until every durable listener acknowledged
+// them. Implementations must be safe for concurrent use.
+type Store interface {
+	// Append persists an event before it is dispatched, and returns its ID.
+	Append(eventName string, arguments []any) (uint64, error)
+
+	// Ack records that the listener with the given ID handled the event.
+	Ack(id uint64, listenerID string) error
+
+	// Complete discards the event, as every durable listener handled it.
+	Complete(id uint64) error
+
+	// Pending returns the events that are not completed, in the order they
+	// were appended.
+	Pending() ([]Record, error)
+}
+
+// Record is a persisted event.
+type Record struct {
+	ID        uint64
+	Event     string
+	Arguments []any
+	Acked     []string // IDs of the listeners that handled the event.
+}
+
+// durableRecord tracks the acknowledgements of a dispatched durable event.
+type durableRecord struct {
+	id        uint64
+	remaining int32 // Durable listeners that have not acknowledged the event.
+}
+
+// WithStore makes the events named eventNames durable. Durable events are
+// persisted in the store before they are dispatched, and acknowledged by each
+// listener added with the WithListenerID option once it returned without an
+// error. Events that are not acknowledged by every such listener are
+// delivered again by ReplayPending, giving at-least-once delivery.
+// Listeners without an ID are called as usual, but are not tracked. Emits
+// without a listener with an ID are not persisted, as none would acknowledge
+// them.
+func WithStore(store Store, eventNames ...string) Option {
+	return func(e *Emitter) {
+		e.store = store
+		if e.durable == nil {
+			e.durable = make(map[string]bool, len(eventNames))
+		}
+
+		for _, eventName := range eventNames {
+			e.durable[eventName] = true
+		}
+	}
+}
+
+// WithListenerID sets the ID of the listener. The ID identifies the listener
+// across restarts in the acknowledgements of durable events, so it must be
+// stable and unique for the event.
+func WithListenerID(id string) ListenerOption {
+	return func(h *handler) {
+		h.id = id
+	}
+}
+
+// ReplayPending asynchronously delivers the pending durable events of the
+// store to the listeners with an ID that have not acknowledged them yet. It
+// should be called on startup, once the listeners are added.
+// Returns the first error of the store, or of the arguments of an event.
+func (e *Emitter) ReplayPending() error {
+	if e.store == nil {
+		return nil
+	}
+
+	records, err := e.store.Pending()
+	if err != nil {
+		return err
+	}
+
+	var first error
+	for _, record := range records {
+		if err := e.replay(record); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+func (e *Emitter) replay(record Record) (err error) {
+	listeners, err := e.getListeners(record.Event)
+	if err != nil && err != ErrEventNotExists {
+		return err
+	}
+
+	acked := make(map[string]bool, len(record.Acked))
+	for _, id := range record.Acked {
+		acked[id] = true
+	}
+
+	durable := 0
+	var pending []*handler
+	for _, listener := range listeners {
+		if len(listener.id) == 0 {
+			continue
+		}
+
+		durable++
+		if !acked[listener.id] {
+			pending = append(pending, listener)
+		}
+	}
+
+	// Events without durable listeners are kept until they can be delivered.
+	if durable == 0 {
+		return nil
+	}
+
+	if len(pending) == 0 {
+		return e.store.Complete(record.ID)
+	}
+
+	// Arguments not matching the listeners are reported instead of panicking.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Replay of event %s failed: %v", record.Event, r)
+		}
+	}()
+
+	tracked := &durableRecord{id: record.ID, remaining: int32(len(pending))}
+
+	return e.dispatch(context.Background(), record.Event, pending, record.Arguments, emitAsync, tracked, nil)
+}
+
+// persist appends the event to the store, unless none of the listeners has an
+// ID, in which case the returned record is nil.
+func (e *Emitter) persist(eventName string, arguments []any, listeners []*handler) (*durableRecord, error) {
+	remaining := int32(0)
+	for _, listener := range listeners {
+		if len(listener.id) != 0 {
+			remaining++
+		}
+	}
+
+	if remaining == 0 {
+		return nil, nil
+	}
+
+	id, err := e.store.Append(eventName, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return &durableRecord{id: id, remaining: remaining}, nil
+}
+
+// deliver calls the listener with its retries, and acknowledges the durable
+// event if the listener succeeded.
+func (e *Emitter) deliver(ctx context.Context, inv invocation, async bool) (int, error) {
+	attempts, err := e.callRetry(ctx, inv, async)
+	if err != nil {
+		return attempts, err
+	}
+
+	return attempts, e.ack(inv.record, inv.listener)
+}
+
+// ack acknowledges the durable event for the listener, and completes it once
+// acknowledged by every durable listener.
+func (e *Emitter) ack(record *durableRecord, listener *handler) error {
+	if record == nil || len(listener.id) == 0 {
+		return nil
+	}
+
+	if err := e.store.Ack(record.id, listener.id); err != nil {
+		return err
+	}
+
+	if atomic.AddInt32(&record.remaining, -1) == 0 {
+		return e.store.Complete(record.id)
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package eventemitter
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStore struct {
+	mu      sync.Mutex
+	nextID  uint64
+	records map[uint64]*Record
+	err     error
+}
+
+func newTestStore() *testStore {
+	return &testStore{nextID: 1, records: make(map[uint64]*Record)}
+}
+
+func (s *testStore) Append(eventName string, arguments []any) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	id := s.nextID
+	s.nextID++
+	s.records[id] = &Record{ID: id, Event: eventName, Arguments: arguments}
+
+	return id, nil
+}
+
+func (s *testStore) Ack(id uint64, listenerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[id].Acked = append(s.records[id].Acked, listenerID)
+
+	return nil
+}
+
+func (s *testStore) Complete(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, id)
+
+	return nil
+}
+
+func (s *testStore) Pending() ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var records []Record
+	for id := uint64(1); id < s.nextID; id++ {
+		if record, ok := s.records[id]; ok {
+			records = append(records, *record)
+		}
+	}
+
+	return records, nil
+}
+
+func TestDurable(t *testing.T) {
+	store := newTestStore()
+	emitter := New(WithStore(store, "durable"))
+
+	errListener := errors.New("listener")
+	emitter.On("durable", func(a int) {}, WithListenerID("first"))
+	emitter.On("durable", func(a int) error {
+		if a == 2 {
+			return errListener
+		}
+
+		return nil
+	}, WithListenerID("second"))
+	emitter.On("durable", func(a int) {})
+	emitter.On("other", func(a int) {})
+
+	// Acknowledged by every listener.
+	assert.NoError(t, emitter.EmitSync("durable", 1))
+
+	// Not acknowledged by the failing listener.
+	assert.Error(t, emitter.EmitSync("durable", 2))
+
+	// Events that are not durable.
+	assert.NoError(t, emitter.EmitSync("other", 3))
+
+	records, _ := store.Pending()
+	if assert.Equal(t, 1, len(records)) {
+		assert.Equal(t, "durable", records[0].Event)
+		assert.Equal(t, []any{2}, records[0].Arguments)
+		assert.Equal(t, []string{"first"}, records[0].Acked)
+	}
+
+	// Emits without a listener with an ID are not persisted.
+	emitter = New(WithStore(store, "durable", "untracked"))
+	emitter.On("untracked", func(a int) {})
+	assert.NoError(t, emitter.EmitSync("untracked", 4))
+	records, _ = store.Pending()
+	assert.Equal(t, 1, len(records))
+
+	// Store errors are returned before dispatching.
+	emitter.On("durable", func(a int) {}, WithListenerID("first"))
+	store.err = errors.New("store")
+	assert.Equal(t, store.err, emitter.EmitSync("durable", 1))
+}
+
+func TestReplayPending(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	store, err := OpenFileStore(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// The process fails before the second listener handled the event.
+	emitter := New(WithStore(store, "billing"))
+	emitter.On("billing", func(id string, amount int) {}, WithListenerID("ledger"))
+	emitter.On("billing", func(id string, amount int) error {
+		return errors.New("unavailable")
+	}, WithListenerID("invoice"))
+
+	assert.Error(t, emitter.EmitSync("billing", "order-1", 100))
+	assert.NoError(t, emitter.Close())
+	assert.NoError(t, store.Close())
+
+	// The event is replayed to the second listener on startup.
+	store, err = OpenFileStore(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer store.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	emitter = New(WithStore(store, "billing"))
+	emitter.On("billing", func(id string, amount int) {
+		assert.Fail(t, "acknowledged listener called")
+	}, WithListenerID("ledger"))
+	emitter.On("billing", func(id string, amount int) {
+		defer wg.Done()
+
+		assert.Equal(t, "order-1", id)
+		assert.Equal(t, 100, amount)
+	}, WithListenerID("invoice"))
+
+	assert.NoError(t, emitter.ReplayPending())
+	wg.Wait()
+	assert.NoError(t, emitter.Close())
+
+	records, err := store.Pending()
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, len(records))
+	}
+}
+
+func TestReplayPendingWithoutListeners(t *testing.T) {
+	store := newTestStore()
+	store.Append("event", []any{1})
+	store.Append("mismatch", []any{"test"})
+
+	emitter := New(WithStore(store, "event", "mismatch"))
+
+	// Events without durable listeners are kept.
+	assert.NoError(t, emitter.ReplayPending())
+	records, _ := store.Pending()
+	assert.Equal(t, 2, len(records))
+
+	// Arguments not matching the listeners are reported.
+	emitter.On("mismatch", func(a int) {}, WithListenerID("listener"))
+	assert.EqualError(t, emitter.ReplayPending(), "Replay of event mismatch failed: "+createTypeErr("mismatch", 1, "int", "string"))
+
+	// Emitters without a store.
+	assert.NoError(t, New().ReplayPending())
+}
@@ -0,0 +1,90 @@
+package eventemitter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EmitError is returned by an emit if any of the listeners failed.
+type EmitError struct {
+	Event  string
+	Errors []error // Errors of the failed listeners, in the order of the listeners.
+}
+
+// PanicError is a recovered panic of a listener.
+type PanicError struct {
+	Event string
+	Value any    // Value passed to panic.
+	Stack []byte // Stack trace of the panicking goroutine.
+}
+
+// ListenerTimeoutError is reported when a listener exceeds its timeout.
+type ListenerTimeoutError struct {
+	Event    string
+	Listener string // Name of the listener function.
+	Timeout  time.Duration
+}
+
+// DepthError is returned by an emit exceeding the maximum depth of nested
+// emits. It matches ErrMaxDepthExceeded with errors.Is.
+type DepthError struct {
+	MaxDepth int
+	Chain    []string // Names of the nested events, from the outermost one.
+}
+
+func (e *EmitError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf("Listeners of event %s failed: %s", e.Event, strings.Join(messages, "; "))
+}
+
+// Unwrap returns the errors of the failed listeners.
+func (e *EmitError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is reports whether any of the errors of the listeners matches the target, so
+// errors.Is looks into them on Go versions without multiple error unwrapping.
+func (e *EmitError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error of the listeners matching the target, so errors.As
+// looks into them on Go versions without multiple error unwrapping.
+func (e *EmitError) As(target any) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("Listener of event %s panicked: %v", e.Event, e.Value)
+}
+
+func (e *ListenerTimeoutError) Error() string {
+	return fmt.Sprintf("Listener %s of event %s timed out after %s", e.Listener, e.Event, e.Timeout)
+}
+
+func (e *DepthError) Error() string {
+	return fmt.Sprintf("Maximum emit depth of %d exceeded: %s", e.MaxDepth, strings.Join(e.Chain, " -> "))
+}
+
+// Is reports whether the target is ErrMaxDepthExceeded.
+func (e *DepthError) Is(target error) bool {
+	return target == ErrMaxDepthExceeded
+}
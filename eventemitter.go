@@ -1,20 +1,78 @@
 package eventemitter
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var (
 	ErrEmptyName      = errors.New("Event name cannot be empty")
 	ErrNotAFunction   = errors.New("Callback must be a function or a pointer to a function")
 	ErrEventNotExists = errors.New("Event does not exist")
+	ErrClosed         = errors.New("Emitter is closed")
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
 )
 
 type Emitter struct {
+	mu        sync.Mutex
 	listeners sync.Map
+	observers atomic.Value // []*observer
+
+	state    sync.RWMutex
+	closed   bool
+	drained  bool          // The coalesced listeners were flushed by Shutdown.
+	done     chan struct{} // Closed by Shutdown, created on demand.
+	inflight sync.WaitGroup
+
+	metrics       Metrics
+	tracer        Tracer
+	convert       bool
+	parallelLimit int
+	timeout       time.Duration
+	deadLetter    func(DeadLetter)
+
+	store   Store
+	durable map[string]bool
+
+	retained map[string]*retention
+	history  *History
+	limits   []*rateLimit
+
+	maxDepth   int
+	depthPanic bool
+}
+
+// Option configures an emitter.
+type Option func(*Emitter)
+
+type emitMode int
+
+const (
+	emitAsync emitMode = iota
+	emitSync
+	emitParallel
+)
+
+// invocation is a listener call prepared by an emit.
+type invocation struct {
+	eventName   string
+	listener    *handler
+	arguments   []any           // Arguments of the emit, used by the fast path.
+	args        []reflect.Value // Checked arguments, used without the fast path.
+	fast        bool            // The listener is called without reflection.
+	withContext bool            // The context is passed as the first argument.
+	record      *durableRecord  // Durable record of the event, if any.
+	outcome     *historyOutcome // Recorded outcome of the call, if any.
 }
 
 type argsError struct {
@@ -30,6 +88,12 @@ type argsTypeError struct {
 	got      reflect.Type
 }
 
+type argsNilError struct {
+	event    string
+	pos      int
+	expected reflect.Type
+}
+
 func (e *argsError) Error() string {
 	return fmt.Sprintf("Wrong number of arguments. Event %s expected %d arguments, got %d.", e.event, e.expected, e.got)
 }
@@ -38,18 +102,40 @@ func (e *argsTypeError) Error() string {
 	return fmt.Sprintf("Wrong argument type. Event %s expected argument %d to be %s, got %s.", e.event, e.pos, e.expected, e.got)
 }
 
-// New returns a new event emitter.
-func New() *Emitter {
-	return &Emitter{}
+func (e *argsNilError) Error() string {
+	return fmt.Sprintf("Wrong argument type. Event %s expected argument %d to be %s, got nil.", e.event, e.pos, e.expected)
+}
+
+// WithParallelLimit limits the number of listeners called concurrently by an
+// EmitParallel call. A limit less than or equal to zero means no limit.
+func WithParallelLimit(limit int) Option {
+	return func(e *Emitter) {
+		e.parallelLimit = limit
+	}
+}
+
+// New returns a new event emitter configured with the given options.
+func New(options ...Option) *Emitter {
+	e := &Emitter{}
+	for _, option := range options {
+		option(e)
+	}
+
+	return e
 }
 
 // AddListener adds a listener for the specified event.
-// Returns an error if the eventName is empty, or the listener is not a function.
+// Returns an error if the eventName is empty, the listener is not a function,
+// the emitter is closed, or the listener doesn't accept the retained emits it
+// would replay, or the coalesced emits it would receive.
 // No checks are made to see if the listener has already been added. Multiple
 // calls passing the same combination of eventName and listener will result in the
 // listener being added, and called, multiple times.
 // By default, event listeners are invoked in the order they are added.
-func (e *Emitter) AddListener(eventName string, listener any) error {
+// The listener can be configured with options, e.g. WithGroup(group).
+// Listeners added with the WithReplay option are called with the retained
+// emits of the event before AddListener returns.
+func (e *Emitter) AddListener(eventName string, listener any, options ...ListenerOption) error {
 	if len(eventName) == 0 {
 		return ErrEmptyName
 	}
@@ -58,18 +144,35 @@ func (e *Emitter) AddListener(eventName string, listener any) error {
 		return ErrNotAFunction
 	}
 
+	if e.isClosed() {
+		return ErrClosed
+	}
+
+	h := newHandler(listener, options)
+	if h.coalescer != nil && !h.coalescer.accepts(h) {
+		return ErrCoalesceListener
+	}
+
+	if err := e.checkRetained(eventName, h); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
 	if listeners, ok := e.listeners.Load(eventName); ok {
-		e.listeners.Store(eventName, append(listeners.([]any), listener))
+		e.listeners.Store(eventName, append(listeners.([]*handler), h))
 	} else {
-		e.listeners.Store(eventName, []any{listener})
+		e.listeners.Store(eventName, []*handler{h})
 	}
+	e.mu.Unlock()
+
+	e.replayRetained(eventName, h)
 
 	return nil
 }
 
-// On is an alias for .AddListener(eventName, listener).
-func (e *Emitter) On(eventName string, listener any) error {
-	return e.AddListener(eventName, listener)
+// On is an alias for .AddListener(eventName, listener, options...).
+func (e *Emitter) On(eventName string, listener any, options ...ListenerOption) error {
+	return e.AddListener(eventName, listener, options...)
 }
 
 // RemoveListener removes the specified listener from the specified event.
@@ -85,17 +188,20 @@ func (e *Emitter) RemoveListener(eventName string, listener any) (ok bool, err e
 		return false, ErrNotAFunction
 	}
 
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	listeners, err := e.getListeners(eventName)
 	if err != nil {
 		return false, err
 	}
 
 	for i := len(listeners) - 1; i >= 0; i-- {
-		if e.isEqual(listener, listeners[i]) {
+		if e.isEqual(listener, listeners[i].fn) {
 			if len(listeners) == 1 {
 				e.listeners.Delete(eventName)
 			} else {
-				e.listeners.Store(eventName, append(listeners[:i], listeners[i+1:]...))
+				e.listeners.Store(eventName, e.without(listeners, i))
 			}
 
 			return true, nil
@@ -112,6 +218,9 @@ func (e *Emitter) Off(eventName string, listener any) (ok bool, err error) {
 
 // RemoveAllListeners removes all listeners, or those of the specified eventName.
 func (e *Emitter) RemoveAllListeners(eventName ...string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	if len(eventName) == 0 {
 		eventName = e.EventNames()
 	}
@@ -121,6 +230,44 @@ func (e *Emitter) RemoveAllListeners(eventName ...string) {
 	}
 }
 
+// RemoveGroup removes the listeners of the specified group from every event.
+// Returns the number of listeners removed.
+func (e *Emitter) RemoveGroup(group string) int {
+	if len(group) == 0 {
+		return 0
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	removed := 0
+	e.listeners.Range(func(eventName, value any) bool {
+		listeners := value.([]*handler)
+
+		remaining := make([]*handler, 0, len(listeners))
+		for _, h := range listeners {
+			if h.group != group {
+				remaining = append(remaining, h)
+			}
+		}
+
+		if len(remaining) == len(listeners) {
+			return true
+		}
+
+		removed += len(listeners) - len(remaining)
+		if len(remaining) == 0 {
+			e.listeners.Delete(eventName)
+		} else {
+			e.listeners.Store(eventName, remaining)
+		}
+
+		return true
+	})
+
+	return removed
+}
+
 // Clear is an alias for .RemoveAllListeners(eventName).
 func (e *Emitter) Clear(eventName ...string) {
 	e.RemoveAllListeners(eventName...)
@@ -129,54 +276,377 @@ func (e *Emitter) Clear(eventName ...string) {
 // Emit asynchronously calls each of the listeners registered for the event
 // named eventName, in the order they were registered, passing the supplied
 // arguments to each.
-// Returns an error if the event does not exist.
+// Returns an error if the event does not exist, or the emitter is closed.
 func (e *Emitter) Emit(eventName string, arguments ...any) error {
-	return e.emit(eventName, arguments, false)
+	return e.emit(context.Background(), eventName, arguments, emitAsync)
+}
+
+// EmitContext is like Emit, but listeners whose first parameter is a
+// context.Context receive ctx, or a context derived from it.
+func (e *Emitter) EmitContext(ctx context.Context, eventName string, arguments ...any) error {
+	return e.emit(ctx, eventName, arguments, emitAsync)
 }
 
 // EmitSync synchronously calls each of the listeners registered for the event
 // named eventName, in the order they were registered, passing the supplied
 // arguments to each.
-// Returns an error if the event does not exist.
+// Returns an error if the event does not exist, or the emitter is closed.
+// Listeners may return an error as their last result; if any of them does,
+// the errors are returned in an *EmitError.
 func (e *Emitter) EmitSync(eventName string, arguments ...any) error {
-	return e.emit(eventName, arguments, true)
+	return e.emit(context.Background(), eventName, arguments, emitSync)
 }
 
-func (e *Emitter) emit(eventName string, arguments []any, sync bool) error {
+// EmitSyncContext is like EmitSync, but listeners whose first parameter is a
+// context.Context receive ctx, or a context derived from it.
+func (e *Emitter) EmitSyncContext(ctx context.Context, eventName string, arguments ...any) error {
+	return e.emit(ctx, eventName, arguments, emitSync)
+}
+
+// EmitParallel concurrently calls each of the listeners registered for the
+// event named eventName, passing the supplied arguments to each, and waits for
+// them to return. The number of concurrent calls can be limited with the
+// WithParallelLimit option.
+// Returns an error if the event does not exist, or the emitter is closed. The
+// errors returned by the listeners, and their recovered panics as *PanicError,
+// are returned in an *EmitError.
+func (e *Emitter) EmitParallel(eventName string, arguments ...any) error {
+	return e.emit(context.Background(), eventName, arguments, emitParallel)
+}
+
+// EmitParallelContext is like EmitParallel, but listeners whose first
+// parameter is a context.Context receive ctx, or a context derived from it.
+func (e *Emitter) EmitParallelContext(ctx context.Context, eventName string, arguments ...any) error {
+	return e.emit(ctx, eventName, arguments, emitParallel)
+}
+
+func (e *Emitter) emit(ctx context.Context, eventName string, arguments []any, mode emitMode) (err error) {
+	if e.isClosed() {
+		e.dropped(eventName, ErrClosed)
+		return ErrClosed
+	}
+
+	if len(eventName) == 0 {
+		return ErrEmptyName
+	}
+
+	if e.maxDepth > 0 {
+		if ctx, err = e.enter(ctx, eventName); err != nil {
+			e.dropped(eventName, ErrMaxDepthExceeded)
+			return err
+		}
+	}
+
+	if len(e.limits) != 0 {
+		if allowed, err := e.limit(ctx, eventName); !allowed {
+			return err
+		}
+	}
+
+	if r := e.retained[eventName]; r != nil {
+		r.add(arguments)
+	}
+
+	e.observe(ctx, eventName, arguments)
+
 	listeners, err := e.getListeners(eventName)
 	if err != nil {
+		if err == ErrEventNotExists {
+			e.dropped(eventName, err)
+
+			if e.history != nil {
+				e.history.record(eventName, arguments, nil, err)
+			}
+		}
+
 		return err
 	}
 
-	args := make([]reflect.Value, 0, len(arguments))
-	for _, arg := range arguments {
-		args = append(args, reflect.ValueOf(arg))
+	if e.metrics != nil {
+		e.metrics.Emitted(eventName)
 	}
 
-	for _, listener := range listeners {
-		fn := reflect.ValueOf(listener)
+	var entry *HistoryEntry
+	if e.history != nil {
+		entry = e.history.record(eventName, arguments, listeners, nil)
+	}
 
-		// If the listener is a pointer to a function, get the function.
-		if fn.Kind() == reflect.Pointer {
-			fn = fn.Elem()
+	if e.tracer != nil {
+		var span Span
+		ctx, span = e.tracer.StartEmit(ctx, eventName)
+		defer func() {
+			span.End(err)
+		}()
+	}
+
+	// Durable events are persisted before they are dispatched.
+	var record *durableRecord
+	if e.store != nil && e.durable[eventName] {
+		if record, err = e.persist(eventName, arguments, listeners); err != nil {
+			return err
 		}
+	}
 
-		// Check the number of arguments and their types.
-		if err := e.checkArguments(eventName, fn, args); err != nil {
-			panic(err)
+	return e.dispatch(ctx, eventName, listeners, arguments, mode, record, entry)
+}
+
+// dispatch calls the listeners with the arguments in the given mode. The
+// durable record and the history entry of the emit are optional.
+func (e *Emitter) dispatch(ctx context.Context, eventName string, listeners []*handler, arguments []any, mode emitMode, record *durableRecord, entry *HistoryEntry) error {
+	// Reflected arguments, built once for the listeners without a fast path.
+	var args []reflect.Value
+
+	var errs []error
+	var parallel []invocation
+
+	for i, listener := range listeners {
+		// Filtered out listeners are skipped, the emit is acknowledged.
+		if listener.filtered(arguments) {
+			if err := e.ack(record, listener); err != nil {
+				errs = append(errs, err)
+			}
+
+			continue
+		}
+
+		// Coalesced listeners are called later, and acknowledge the emit
+		// once called.
+		if listener.coalescer != nil {
+			if err := e.coalesce(ctx, eventName, listener, arguments, record); err != nil {
+				errs = append(errs, err)
+			}
+
+			continue
+		}
+
+		var inv invocation
+		inv, args = e.prepare(eventName, listener, arguments, args)
+		inv.record = record
+
+		if entry != nil {
+			inv.outcome = &historyOutcome{history: e.history, entry: entry, index: i}
 		}
 
 		// Call the listener.
-		if sync {
-			fn.Call(args)
-		} else {
-			go fn.Call(args)
+		switch mode {
+		case emitSync:
+			if _, err := e.deliver(ctx, inv, false); err != nil {
+				errs = append(errs, err)
+			}
+		case emitParallel:
+			parallel = append(parallel, inv)
+		default:
+			if !e.startAsync() {
+				e.dropped(eventName, ErrClosed)
+				return ErrClosed
+			}
+
+			if e.metrics != nil {
+				e.metrics.AsyncStarted(eventName)
+			}
+
+			go func(ctx context.Context, inv invocation) {
+				defer e.inflight.Done()
+
+				if e.metrics != nil {
+					defer e.metrics.AsyncFinished(eventName)
+				}
+
+				if attempts, err := e.deliver(ctx, inv, true); err != nil {
+					e.deadLettered(inv, attempts, err)
+				}
+			}(ctx, inv)
 		}
 	}
 
+	if mode == emitParallel {
+		errs = e.callParallel(ctx, parallel)
+	}
+
+	if len(errs) != 0 {
+		return &EmitError{Event: eventName, Errors: errs}
+	}
+
 	return nil
 }
 
+// prepare prepares the call of the listener. The reflected arguments are built
+// from the arguments on first use, and returned to be reused for the next
+// listeners. Panics if the arguments don't match the listener.
+func (e *Emitter) prepare(eventName string, listener *handler, arguments []any, args []reflect.Value) (invocation, []reflect.Value) {
+	inv, args, err := e.check(eventName, listener, arguments, args)
+	if err != nil {
+		panic(err)
+	}
+
+	return inv, args
+}
+
+// check is like prepare, but returns the error of the arguments not matching
+// the listener.
+func (e *Emitter) check(eventName string, listener *handler, arguments []any, args []reflect.Value) (invocation, []reflect.Value, error) {
+	inv := invocation{
+		eventName: eventName,
+		listener:  listener,
+		arguments: arguments,
+		fast:      listener.accepts(arguments),
+	}
+
+	if inv.fast {
+		return inv, args, nil
+	}
+
+	if args == nil {
+		args = make([]reflect.Value, 0, len(arguments))
+		for _, arg := range arguments {
+			args = append(args, reflect.ValueOf(arg))
+		}
+	}
+
+	// Listeners accepting a context receive it as the first argument.
+	inv.withContext = listener.context && (listener.emitContext || e.acceptsContext(listener.fnType, args))
+
+	// Check the number of arguments and their types.
+	var err error
+	inv.args, err = e.checkArguments(eventName, listener.fnType, args, inv.withContext)
+
+	return inv, args, err
+}
+
+// callParallel calls the listeners concurrently, at most e.parallelLimit at a
+// time, and waits for them to return. Returns the errors returned by the
+// listeners and their recovered panics, in the order of the listeners.
+func (e *Emitter) callParallel(ctx context.Context, invocations []invocation) []error {
+	var sem chan struct{}
+	if e.parallelLimit > 0 {
+		sem = make(chan struct{}, e.parallelLimit)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]error, len(invocations))
+
+	for i, inv := range invocations {
+		if sem != nil {
+			sem <- struct{}{}
+		}
+
+		wg.Add(1)
+		go func(i int, inv invocation) {
+			defer wg.Done()
+
+			if sem != nil {
+				defer func() { <-sem }()
+			}
+
+			defer func() {
+				if r := recover(); r != nil {
+					results[i] = &PanicError{Event: inv.eventName, Value: r, Stack: debug.Stack()}
+				}
+			}()
+
+			_, results[i] = e.deliver(ctx, inv, false)
+		}(i, inv)
+	}
+
+	wg.Wait()
+
+	var errs []error
+	for _, err := range results {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// call calls the listener, reporting its latency and panics to the metrics and
+// the tracer. Returns the error returned by the listener, if any.
+func (e *Emitter) call(ctx context.Context, inv invocation, async bool) (err error) {
+	if e.tracer != nil {
+		var span Span
+		ctx, span = e.tracer.StartListener(ctx, inv.eventName, async)
+		defer func() {
+			if r := recover(); r != nil {
+				span.End(fmt.Errorf("panic: %v", r))
+				panic(r)
+			}
+
+			span.End(err)
+		}()
+	}
+
+	if inv.outcome != nil {
+		start := time.Now()
+		defer func() {
+			r := recover()
+			inv.outcome.done(time.Since(start), err, r)
+
+			if r != nil {
+				panic(r)
+			}
+		}()
+	}
+
+	if e.metrics != nil {
+		start := time.Now()
+		defer func() {
+			e.metrics.ListenerCalled(inv.eventName, time.Since(start))
+
+			if r := recover(); r != nil {
+				e.metrics.ListenerPanicked(inv.eventName)
+				panic(r)
+			}
+		}()
+	}
+
+	return inv.call(ctx)
+}
+
+func (e *Emitter) dropped(eventName string, reason error) {
+	if e.metrics != nil {
+		e.metrics.Dropped(eventName, reason)
+	}
+}
+
+// Shutdown closes the emitter. New emits and listeners are rejected with
+// ErrClosed, the pending calls of coalesced listeners are made, then Shutdown
+// waits for the in-flight asynchronous listeners to return, or for the context
+// to expire, and removes all listeners.
+// Returns the context's error if it expired before the listeners returned.
+func (e *Emitter) Shutdown(ctx context.Context) error {
+	e.state.Lock()
+	if !e.closed && e.done != nil {
+		close(e.done)
+	}
+	e.closed = true
+	e.state.Unlock()
+
+	e.flushCoalesced()
+
+	done := make(chan struct{})
+	go func() {
+		e.inflight.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	e.RemoveAllListeners()
+
+	return err
+}
+
+// Close is an alias for .Shutdown(context.Background()).
+func (e *Emitter) Close() error {
+	return e.Shutdown(context.Background())
+}
+
 // EventNames returns a slice of strings listing the events for which the emitter
 // has registered listeners.
 func (e *Emitter) EventNames() []string {
@@ -194,7 +664,17 @@ func (e *Emitter) EventNames() []string {
 // Listeners returns a slice of functions registered to the specified event.
 // Returns an error if the event does not exist.
 func (e *Emitter) Listeners(eventName string) ([]any, error) {
-	return e.getListeners(eventName)
+	listeners, err := e.getListeners(eventName)
+	if err != nil {
+		return nil, err
+	}
+
+	fns := make([]any, 0, len(listeners))
+	for _, listener := range listeners {
+		fns = append(fns, listener.fn)
+	}
+
+	return fns, nil
 }
 
 // ListenersCount returns the number of listeners for the specified event.
@@ -209,48 +689,154 @@ func (e *Emitter) ListenerCount(eventName string) (int, error) {
 	return len(listeners), nil
 }
 
-func (e *Emitter) getListeners(eventName string) ([]any, error) {
+func (e *Emitter) isClosed() bool {
+	e.state.RLock()
+	defer e.state.RUnlock()
+
+	return e.closed
+}
+
+// closing returns a channel that is closed when the emitter is closed.
+func (e *Emitter) closing() <-chan struct{} {
+	e.state.Lock()
+	defer e.state.Unlock()
+
+	if e.done == nil {
+		e.done = make(chan struct{})
+		if e.closed {
+			close(e.done)
+		}
+	}
+
+	return e.done
+}
+
+// startAsync registers an asynchronous listener call, unless the emitter is
+// closed. The call must be finished with e.inflight.Done().
+func (e *Emitter) startAsync() bool {
+	e.state.RLock()
+	defer e.state.RUnlock()
+
+	if e.closed {
+		return false
+	}
+
+	e.inflight.Add(1)
+
+	return true
+}
+
+func (e *Emitter) getListeners(eventName string) ([]*handler, error) {
 	if len(eventName) == 0 {
 		return nil, ErrEmptyName
 	}
 
 	if listeners, ok := e.listeners.Load(eventName); ok {
-		return listeners.([]any), nil
+		return listeners.([]*handler), nil
 	}
 
 	return nil, ErrEventNotExists
 }
 
-func (e *Emitter) checkArguments(eventName string, fn reflect.Value, args []reflect.Value) error {
-	fnType := fn.Type()
+// without returns a copy of listeners without the element at index i, so
+// emits holding the previous slice are not affected.
+func (e *Emitter) without(listeners []*handler, i int) []*handler {
+	remaining := make([]*handler, 0, len(listeners)-1)
+	remaining = append(remaining, listeners[:i]...)
+
+	return append(remaining, listeners[i+1:]...)
+}
+
+// checkArguments checks the arguments against the parameters of the listener.
+// If withContext is true, the first parameter is the context, which is not
+// part of the arguments. Returns the arguments to call the listener with, in
+// which nil arguments are replaced by the zero value of their parameter, and
+// convertible arguments are converted if the conversion is enabled.
+func (e *Emitter) checkArguments(eventName string, fnType reflect.Type, args []reflect.Value, withContext bool) ([]reflect.Value, error) {
 	isVariadic := fnType.IsVariadic()
-	noParams := fnType.NumIn()
+
+	offset := 0
+	if withContext {
+		offset = 1
+	}
+	noParams := fnType.NumIn() - offset
 
 	// Check arguments length.
 	if isVariadic {
 		if (noParams - 1) > len(args) {
-			return &argsError{eventName, noParams - 1, len(args)}
+			return nil, &argsError{eventName, noParams - 1, len(args)}
 		}
 	} else if noParams != len(args) {
-		return &argsError{eventName, noParams, len(args)}
+		return nil, &argsError{eventName, noParams, len(args)}
 	}
 
 	// Check arguments type.
+	in, copied := args, false
 	for i := 0; i < noParams; i++ {
+		paramType := fnType.In(i + offset)
+
+		last := i + 1
 		if isVariadic && i == (noParams-1) {
-			args = args[i:] // Variadic arguments.
+			paramType = paramType.Elem()
+			last = len(args) // Variadic arguments.
+		}
+
+		for j := i; j < last; j++ {
+			if args[j].IsValid() {
+				if args[j].Type().AssignableTo(paramType) {
+					continue
+				}
+
+				if !e.convert || !e.canConvert(args[j].Type(), paramType) {
+					return nil, &argsTypeError{eventName, i + 1, paramType, args[j].Type()}
+				}
 
-			for j := 0; j < len(args); j++ {
-				if !args[j].Type().AssignableTo(fnType.In(i).Elem()) {
-					return &argsTypeError{eventName, i + 1, fnType.In(i).Elem(), args[j].Type()}
+				if !copied {
+					in, copied = append([]reflect.Value(nil), args...), true
 				}
+				in[j] = args[j].Convert(paramType)
+
+				continue
+			}
+
+			// Nil argument.
+			if !isNilable(paramType) {
+				return nil, &argsNilError{eventName, i + 1, paramType}
 			}
-		} else if !args[i].Type().AssignableTo(fnType.In(i)) {
-			return &argsTypeError{eventName, i + 1, fnType.In(i), args[i].Type()}
+
+			if !copied {
+				in, copied = append([]reflect.Value(nil), args...), true
+			}
+			in[j] = reflect.Zero(paramType)
 		}
 	}
 
-	return nil
+	return in, nil
+}
+
+// acceptsContext reports whether the context parameter of a listener is not
+// supplied by the arguments. A nil first argument is the context if the
+// listener takes exactly the arguments, context included.
+func (e *Emitter) acceptsContext(fnType reflect.Type, args []reflect.Value) bool {
+	if len(args) == 0 {
+		return true
+	}
+
+	if !args[0].IsValid() {
+		return fnType.IsVariadic() || fnType.NumIn() != len(args)
+	}
+
+	return !args[0].Type().Implements(contextType)
+}
+
+// isNilable reports whether nil can be assigned to a value of the type.
+func isNilable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice, reflect.UnsafePointer:
+		return true
+	}
+
+	return false
 }
 
 func (e *Emitter) isFunction(fn any) bool {
@@ -1,9 +1,13 @@
 package eventemitter
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -48,7 +52,7 @@ func TestAddListenerOn(t *testing.T) {
 	err = emitter.On("other_event", &event)
 	if assert.NoError(t, err) {
 		listeners, ok := emitter.listeners.Load("other_event")
-		assert.Equal(t, 3, len(listeners.([]any)))
+		assert.Equal(t, 3, len(listeners.([]*handler)))
 		assert.True(t, ok)
 	}
 
@@ -58,7 +62,7 @@ func TestAddListenerOn(t *testing.T) {
 		assert.NoError(t, err)
 	}
 	listeners, ok := emitter.listeners.Load("same_event")
-	assert.Equal(t, 10, len(listeners.([]any)))
+	assert.Equal(t, 10, len(listeners.([]*handler)))
 	assert.True(t, ok)
 
 	// Empty event name.
@@ -111,7 +115,7 @@ func TestRemoveListenerOff(t *testing.T) {
 	if assert.NoError(t, err) {
 		listeners, ok := emitter.listeners.Load("event_third")
 		assert.True(t, ok)
-		assert.Equal(t, 1, len(listeners.([]any)))
+		assert.Equal(t, 1, len(listeners.([]*handler)))
 	}
 
 	_, err = emitter.Off("event_third", &event_2)
@@ -176,7 +180,7 @@ func TestRemoveAllListenersClear(t *testing.T) {
 
 	listeners, ok := emitter.listeners.Load("event_3")
 	assert.True(t, ok)
-	assert.Equal(t, 2, len(listeners.([]any)))
+	assert.Equal(t, 2, len(listeners.([]*handler)))
 
 	// Register events.
 	newEvent := func() {}
@@ -194,6 +198,42 @@ func TestRemoveAllListenersClear(t *testing.T) {
 	}
 }
 
+func TestRemoveGroup(t *testing.T) {
+	emitter := New()
+
+	event := func() {}
+	emitter.On("event_1", event, WithGroup("plugin-a"))
+	emitter.On("event_1", &event)
+	emitter.On("event_2", func() {}, WithGroup("plugin-a"))
+	emitter.On("event_2", event, WithGroup("plugin-b"))
+	emitter.On("event_3", &event, WithGroup("plugin-a"))
+
+	// Remove a group across events.
+	assert.Equal(t, 3, emitter.RemoveGroup("plugin-a"))
+
+	listeners, ok := emitter.listeners.Load("event_1")
+	if assert.True(t, ok) {
+		assert.Equal(t, 1, len(listeners.([]*handler)))
+	}
+
+	listeners, ok = emitter.listeners.Load("event_2")
+	if assert.True(t, ok) {
+		assert.Equal(t, 1, len(listeners.([]*handler)))
+		assert.Equal(t, "plugin-b", listeners.([]*handler)[0].group)
+	}
+
+	_, ok = emitter.listeners.Load("event_3")
+	assert.False(t, ok)
+
+	// Group does not exist.
+	assert.Equal(t, 0, emitter.RemoveGroup("plugin-a"))
+
+	// Empty group name.
+	assert.Equal(t, 0, emitter.RemoveGroup(""))
+	listeners, _ = emitter.listeners.Load("event_1")
+	assert.Equal(t, 1, len(listeners.([]*handler)))
+}
+
 func TestEmit(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(11)
@@ -337,6 +377,215 @@ func TestEmitSync(t *testing.T) {
 	}
 }
 
+func TestShutdown(t *testing.T) {
+	emitter := New()
+
+	// Wait for in-flight listeners.
+	release := make(chan struct{})
+	finished := false
+	emitter.On("event", func() {
+		<-release
+		time.Sleep(10 * time.Millisecond)
+		finished = true
+	})
+	assert.NoError(t, emitter.Emit("event"))
+
+	go close(release)
+	assert.NoError(t, emitter.Shutdown(context.Background()))
+	assert.True(t, finished)
+	assert.Equal(t, 0, len(emitter.EventNames()))
+
+	// Reject new emits and listeners.
+	assert.Equal(t, ErrClosed, emitter.Emit("event"))
+	assert.Equal(t, ErrClosed, emitter.EmitSync("event"))
+	assert.Equal(t, ErrClosed, emitter.On("event", func() {}))
+	assert.NoError(t, emitter.Close())
+}
+
+func TestShutdownContextExpired(t *testing.T) {
+	emitter := New()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	emitter.On("event", func() {
+		<-release
+	})
+	assert.NoError(t, emitter.Emit("event"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := emitter.Shutdown(ctx)
+	if assert.Error(t, err) {
+		assert.Equal(t, context.DeadlineExceeded, err)
+	}
+	assert.Equal(t, 0, len(emitter.EventNames()))
+}
+
+func TestEmitSyncErrors(t *testing.T) {
+	emitter := New()
+
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	emitter.On("event", func() error { return errFirst })
+	emitter.On("event", func() error { return nil })
+	emitter.On("event", func(ctx context.Context) (int, error) { return 0, errSecond })
+	emitter.On("event", func() bool { return false })
+
+	err := emitter.EmitSync("event")
+	if assert.Error(t, err) {
+		var emitErr *EmitError
+		if assert.ErrorAs(t, err, &emitErr) {
+			assert.Equal(t, "event", emitErr.Event)
+			assert.Equal(t, []error{errFirst, errSecond}, emitErr.Errors)
+
+			// The errors are matched without multiple error unwrapping.
+			assert.True(t, emitErr.Is(errSecond))
+			assert.False(t, emitErr.Is(ErrClosed))
+
+			var argsErr *argsError
+			assert.False(t, emitErr.As(&argsErr))
+		}
+		assert.ErrorIs(t, err, errFirst)
+		assert.Equal(t, "Listeners of event event failed: first; second", err.Error())
+	}
+
+	// Listeners returning no errors.
+	emitter.On("no_errors", func() error { return nil })
+	assert.NoError(t, emitter.EmitSync("no_errors"))
+}
+
+func TestEmitParallel(t *testing.T) {
+	emitter := New()
+
+	// Listeners run concurrently.
+	var wg sync.WaitGroup
+	wg.Add(3)
+	for i := 0; i < 3; i++ {
+		emitter.On("event", func(a int) {
+			wg.Done()
+			wg.Wait()
+			assert.Equal(t, 1, a)
+		})
+	}
+	assert.NoError(t, emitter.EmitParallel("event", 1))
+
+	// Errors and panics are returned in the order of the listeners.
+	errListener := errors.New("listener")
+	emitter.On("errors", func() { panic("first") })
+	emitter.On("errors", func() {})
+	emitter.On("errors", func(ctx context.Context) error { return errListener })
+
+	err := emitter.EmitParallelContext(context.Background(), "errors")
+	var emitErr *EmitError
+	if assert.ErrorAs(t, err, &emitErr) && assert.Equal(t, 2, len(emitErr.Errors)) {
+		var panicErr *PanicError
+		if assert.ErrorAs(t, emitErr.Errors[0], &panicErr) {
+			assert.Equal(t, "errors", panicErr.Event)
+			assert.Equal(t, "first", panicErr.Value)
+			assert.NotEmpty(t, panicErr.Stack)
+			assert.Equal(t, "Listener of event errors panicked: first", panicErr.Error())
+		}
+		assert.Equal(t, errListener, emitErr.Errors[1])
+
+		panicErr = nil
+		assert.True(t, emitErr.As(&panicErr))
+		assert.Equal(t, "first", panicErr.Value)
+	}
+
+	// Wrong arguments.
+	assert.PanicsWithError(t, (&argsError{"event", 1, 0}).Error(), func() { emitter.EmitParallel("event") })
+
+	// Emit without event name.
+	assert.Equal(t, ErrEmptyName, emitter.EmitParallel(""))
+
+	// Emitting an event that doesn't exist.
+	assert.Equal(t, ErrEventNotExists, emitter.EmitParallel("event_not_exists"))
+}
+
+func TestEmitParallelLimit(t *testing.T) {
+	emitter := New(WithParallelLimit(2))
+
+	var mu sync.Mutex
+	running, peak := 0, 0
+	for i := 0; i < 6; i++ {
+		emitter.On("event", func() {
+			mu.Lock()
+			running++
+			if running > peak {
+				peak = running
+			}
+			mu.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+		})
+	}
+
+	assert.NoError(t, emitter.EmitParallel("event"))
+	assert.Equal(t, 2, peak)
+}
+
+type testContextKey struct{}
+
+func TestEmitContext(t *testing.T) {
+	emitter := New()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	ctx := context.WithValue(context.Background(), testContextKey{}, "value")
+
+	// Listeners accepting a context receive the context of the emit.
+	emitter.On("context", func(ctx context.Context, a int, b ...string) {
+		assert.Equal(t, "value", ctx.Value(testContextKey{}))
+		assert.Equal(t, 1, a)
+		assert.Equal(t, []string{"t", "e", "s", "t"}, b)
+	})
+	emitter.On("context", func(a int, b ...string) {
+		assert.Equal(t, 1, a)
+	})
+	assert.NoError(t, emitter.EmitSyncContext(ctx, "context", 1, "t", "e", "s", "t"))
+
+	emitter.On("async", func(ctx context.Context) {
+		defer wg.Done()
+		assert.Equal(t, "value", ctx.Value(testContextKey{}))
+	})
+	assert.NoError(t, emitter.EmitContext(ctx, "async"))
+
+	// Listeners without a context emitted without one.
+	emitter.On("background", func(ctx context.Context) {
+		assert.Equal(t, context.Background(), ctx)
+	})
+	assert.NoError(t, emitter.EmitSync("background"))
+
+	// A context supplied as an argument is passed as is.
+	emitter.On("argument", func(ctx context.Context) {
+		assert.Equal(t, "value", ctx.Value(testContextKey{}))
+	})
+	assert.NoError(t, emitter.EmitSync("argument", ctx))
+
+	// Listeners added with WithEmitContext receive the context of the emit,
+	// and the context supplied as an argument.
+	emitter.On("emit_context", func(emitCtx context.Context, args ...any) {
+		assert.Equal(t, context.Background(), emitCtx)
+		assert.Equal(t, []any{ctx, 1}, args)
+	}, WithEmitContext())
+	assert.NoError(t, emitter.EmitSync("emit_context", ctx, 1))
+
+	// Wrong number of arguments.
+	emitter.On("panic", func(ctx context.Context, a int) {})
+	assert.PanicsWithError(t, (&argsError{"panic", 1, 0}).Error(), func() { emitter.EmitSyncContext(ctx, "panic") })
+	assert.PanicsWithError(t, createTypeErr("panic", 1, "int", "string"), func() { emitter.EmitSyncContext(ctx, "panic", "test") })
+
+	wg.Wait()
+}
+
 func createTypeErr(event string, pos int, expected string, got string) string {
 	return fmt.Sprintf("Wrong argument type. Event %s expected argument %d to be %s, got %s.", event, pos, expected, got)
 }
@@ -389,6 +638,61 @@ func TestPanic(t *testing.T) {
 	assert.NotPanics(t, func() { emitter.EmitSync("panic_any_type", true, "test", make(chan int)) })
 }
 
+func TestEmitNil(t *testing.T) {
+	emitter := New()
+
+	// Pointer, interface, map, slice, channel and function parameters.
+	emitter.On("nilable", func(p *testType, a any, e error, m map[string]int, s []int, c chan int, f func()) {
+		assert.Nil(t, p)
+		assert.Nil(t, a)
+		assert.Nil(t, e)
+		assert.Nil(t, m)
+		assert.Nil(t, s)
+		assert.Nil(t, c)
+		assert.Nil(t, f)
+	})
+	assert.NotPanics(t, func() { emitter.EmitSync("nilable", nil, nil, nil, nil, nil, nil, nil) })
+
+	// Variadic parameters.
+	emitter.On("variadic", func(a int, p ...*testType) {
+		assert.Equal(t, []*testType{nil, {"test"}, nil}, p)
+	})
+	assert.NotPanics(t, func() { emitter.EmitSync("variadic", 1, nil, &testType{"test"}, nil) })
+
+	emitter.On("variadic_any", func(args ...any) {
+		assert.Equal(t, []any{nil, 1}, args)
+	})
+	emitter.On("variadic_any", func(a any, args ...any) {
+		assert.Nil(t, a)
+		assert.Equal(t, []any{1}, args)
+	})
+	assert.NotPanics(t, func() { emitter.EmitSync("variadic_any", nil, 1) })
+
+	// Context parameter.
+	emitter.On("context", func(ctx context.Context, p *testType) {
+		assert.NotNil(t, ctx)
+		assert.Nil(t, p)
+	})
+	assert.NotPanics(t, func() { emitter.EmitSync("context", nil) })
+
+	// A nil context supplied as an argument.
+	emitter.On("nil_context", func(ctx context.Context) {
+		assert.Nil(t, ctx)
+	})
+	assert.NotPanics(t, func() { emitter.EmitSync("nil_context", nil) })
+
+	// Parameters that cannot be nil.
+	emitter.On("not_nilable", func(a int, b string, c testType) {})
+	assert.PanicsWithError(t, "Wrong argument type. Event not_nilable expected argument 2 to be string, got nil.", func() { emitter.EmitSync("not_nilable", 1, nil, testType{}) })
+	assert.PanicsWithError(t, (&argsNilError{"not_nilable", 3, reflect.TypeOf(testType{})}).Error(), func() { emitter.EmitSync("not_nilable", 1, "test", nil) })
+
+	emitter.On("not_nilable_variadic", func(a ...int) {})
+	assert.PanicsWithError(t, (&argsNilError{"not_nilable_variadic", 1, reflect.TypeOf(0)}).Error(), func() { emitter.EmitSync("not_nilable_variadic", 1, nil) })
+
+	emitter.On("not_nilable_fast", func(a string) {})
+	assert.PanicsWithError(t, (&argsNilError{"not_nilable_fast", 1, reflect.TypeOf("")}).Error(), func() { emitter.EmitSync("not_nilable_fast", nil) })
+}
+
 func TestEventNames(t *testing.T) {
 	emitter := New()
 
@@ -548,6 +852,66 @@ func TestIsEqual(t *testing.T) {
 	assert.False(t, emitter.isEqual(event_3, event_other_3))
 }
 
+func TestEmitSyncFastPath(t *testing.T) {
+	emitter := New()
+
+	calls := []string{}
+	emitter.On("event", func() { calls = append(calls, "no_args") })
+	emitter.On("any", func(a any) { calls = append(calls, fmt.Sprint("any ", a)) })
+	emitter.On("variadic", func(args ...any) { calls = append(calls, fmt.Sprint("variadic ", args)) })
+	emitter.On("typed", func(a string) { calls = append(calls, "string "+a) })
+	emitter.On("int", func(a int) { calls = append(calls, fmt.Sprint("int ", a)) })
+	emitter.On("bool", func(a bool) { calls = append(calls, fmt.Sprint("bool ", a)) })
+
+	// Pointers are dereferenced on every emit.
+	event := func(a string) { calls = append(calls, "pointer "+a) }
+	emitter.On("typed", &event)
+
+	assert.NoError(t, emitter.EmitSync("event"))
+	assert.NoError(t, emitter.EmitSync("any", 1))
+	assert.NoError(t, emitter.EmitSync("variadic", 1, "test"))
+	assert.NoError(t, emitter.EmitSync("variadic"))
+	assert.NoError(t, emitter.EmitSync("typed", "test"))
+	event = func(a string) { calls = append(calls, "reassigned "+a) }
+	assert.NoError(t, emitter.EmitSync("typed", "test"))
+	assert.NoError(t, emitter.EmitSync("int", 42))
+	assert.NoError(t, emitter.EmitSync("bool", true))
+
+	assert.Equal(t, []string{
+		"no_args",
+		"any 1",
+		"variadic [1 test]",
+		"variadic []",
+		"string test",
+		"pointer test",
+		"string test",
+		"reassigned test",
+		"int 42",
+		"bool true",
+	}, calls)
+
+	// Mismatching arguments fall back to the checked path.
+	assert.PanicsWithError(t, (&argsError{"event", 0, 1}).Error(), func() { emitter.EmitSync("event", 1) })
+	assert.PanicsWithError(t, (&argsError{"any", 1, 0}).Error(), func() { emitter.EmitSync("any") })
+	assert.PanicsWithError(t, createTypeErr("typed", 1, "string", "int"), func() { emitter.EmitSync("typed", 1) })
+	assert.PanicsWithError(t, createTypeErr("int", 1, "int", "int64"), func() { emitter.EmitSync("int", int64(1)) })
+}
+
+func TestEmitSyncAllocations(t *testing.T) {
+	emitter := New()
+
+	event := func(a string) {}
+	emitter.On("no_args", func() {})
+	emitter.On("string", event)
+	emitter.On("string", &event)
+
+	// Common signatures are called without allocations.
+	assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() { emitter.EmitSync("no_args") }))
+
+	args := []any{"test"}
+	assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() { emitter.EmitSync("string", args...) }))
+}
+
 func BenchmarkAddListener(b *testing.B) {
 	emitter := New()
 
@@ -571,6 +935,7 @@ func BenchmarkEmitSync(b *testing.B) {
 	emitter := New()
 	emitter.AddListener("event", func() {})
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		emitter.EmitSync("event")
@@ -582,6 +947,7 @@ func BenchmarkEmitSyncPointer(b *testing.B) {
 	testEvent := func() {}
 	emitter.AddListener("event", &testEvent)
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		emitter.EmitSync("event")
@@ -592,6 +958,7 @@ func BenchmarkEmitSyncWithArguments(b *testing.B) {
 	emitter := New()
 	emitter.AddListener("event", func(a int, b string, c bool) {})
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		emitter.EmitSync("event", 1, "test", true)
@@ -603,6 +970,7 @@ func BenchmarkEmitSyncPointerWithArguments(b *testing.B) {
 	testEvent := func(a int, b string, c bool) {}
 	emitter.AddListener("event", &testEvent)
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		emitter.EmitSync("event", 1, "test", true)
@@ -613,6 +981,7 @@ func BenchmarkEmitSyncVariadic(b *testing.B) {
 	emitter := New()
 	emitter.AddListener("event", func(a int, b bool, c ...any) {})
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		emitter.EmitSync("event", 1, true, "test", false, 1000)
@@ -624,6 +993,7 @@ func BenchmarkEmitSyncPointerVariadic(b *testing.B) {
 	testEvent := func(a int, b bool, c ...any) {}
 	emitter.AddListener("event", &testEvent)
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		emitter.EmitSync("event", 1, true, "test", false, 1000)
@@ -657,3 +1027,36 @@ func BenchmarkRemoveListenerPointer(b *testing.B) {
 		emitter.RemoveListener("event", &testEvent)
 	}
 }
+
+func BenchmarkEmitSyncAny(b *testing.B) {
+	emitter := New()
+	emitter.AddListener("event", func(a any) {})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		emitter.EmitSync("event", "test")
+	}
+}
+
+func BenchmarkEmitSyncVariadicAny(b *testing.B) {
+	emitter := New()
+	emitter.AddListener("event", func(args ...any) {})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		emitter.EmitSync("event", 1, true, "test")
+	}
+}
+
+func BenchmarkEmitSyncString(b *testing.B) {
+	emitter := New()
+	emitter.AddListener("event", func(a string) {})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		emitter.EmitSync("event", "test")
+	}
+}
@@ -0,0 +1,111 @@
+package eventemittertest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// TestingT is the subset of testing.TB used by the assertions.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// AssertEmitted asserts that the event was emitted. If arguments are given,
+// the event must have been emitted with equal arguments.
+func AssertEmitted(t TestingT, rec *Recorder, name string, arguments ...any) bool {
+	t.Helper()
+
+	if emitted(rec, name, arguments) {
+		return true
+	}
+
+	if len(arguments) == 0 {
+		t.Errorf("Event %s was not emitted.\nEmitted: %s", name, summary(rec))
+	} else {
+		t.Errorf("Event %s was not emitted with arguments %v.\nEmitted: %s", name, arguments, summary(rec))
+	}
+
+	return false
+}
+
+// AssertNotEmitted asserts that the event was not emitted.
+func AssertNotEmitted(t TestingT, rec *Recorder, name string) bool {
+	t.Helper()
+
+	if events := rec.Event(name); len(events) != 0 {
+		t.Errorf("Event %s was emitted %d times.", name, len(events))
+		return false
+	}
+
+	return true
+}
+
+// AssertOrder asserts that the events were emitted in the given order. Other
+// emits may occur between them.
+func AssertOrder(t TestingT, rec *Recorder, names ...string) bool {
+	t.Helper()
+
+	i := 0
+	for _, event := range rec.Events() {
+		if i < len(names) && event.Name == names[i] {
+			i++
+		}
+	}
+
+	if i == len(names) {
+		return true
+	}
+
+	t.Errorf("Events were not emitted in order %s.\nEmitted: %s", strings.Join(names, ", "), summary(rec))
+
+	return false
+}
+
+// EventuallyEmitted asserts that the event is emitted within the timeout.
+func EventuallyEmitted(t TestingT, rec *Recorder, name string, timeout time.Duration) bool {
+	t.Helper()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		changed := rec.wait()
+		if emitted(rec, name, nil) {
+			return true
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			t.Errorf("Event %s was not emitted within %s.\nEmitted: %s", name, timeout, summary(rec))
+			return false
+		}
+	}
+}
+
+func emitted(rec *Recorder, name string, arguments []any) bool {
+	for _, event := range rec.Event(name) {
+		if len(arguments) == 0 || reflect.DeepEqual(arguments, event.Arguments) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func summary(rec *Recorder) string {
+	events := rec.Events()
+	if len(events) == 0 {
+		return "none"
+	}
+
+	emits := make([]string, 0, len(events))
+	for _, event := range events {
+		emits = append(emits, fmt.Sprintf("%s%v", event.Name, event.Arguments))
+	}
+
+	return strings.Join(emits, ", ")
+}
@@ -0,0 +1,85 @@
+package eventemittertest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/attilabuti/eventemitter/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockT struct {
+	errors []string
+}
+
+func (m *mockT) Helper() {}
+
+func (m *mockT) Errorf(format string, args ...any) {
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertEmitted(t *testing.T) {
+	emitter := eventemitter.New()
+	rec := NewRecorder(emitter)
+
+	emitter.EmitSync("event", 1, "test")
+
+	mock := &mockT{}
+	assert.True(t, AssertEmitted(mock, rec, "event"))
+	assert.True(t, AssertEmitted(mock, rec, "event", 1, "test"))
+	assert.Empty(t, mock.errors)
+
+	assert.False(t, AssertEmitted(mock, rec, "event", 2, "test"))
+	assert.False(t, AssertEmitted(mock, rec, "missing"))
+	if assert.Equal(t, 2, len(mock.errors)) {
+		assert.Equal(t, "Event event was not emitted with arguments [2 test].\nEmitted: event[1 test]", mock.errors[0])
+		assert.Equal(t, "Event missing was not emitted.\nEmitted: event[1 test]", mock.errors[1])
+	}
+}
+
+func TestAssertNotEmitted(t *testing.T) {
+	emitter := eventemitter.New()
+	rec := NewRecorder(emitter)
+
+	emitter.EmitSync("event")
+
+	mock := &mockT{}
+	assert.True(t, AssertNotEmitted(mock, rec, "missing"))
+	assert.False(t, AssertNotEmitted(mock, rec, "event"))
+	assert.Equal(t, []string{"Event event was emitted 1 times."}, mock.errors)
+}
+
+func TestAssertOrder(t *testing.T) {
+	emitter := eventemitter.New()
+	rec := NewRecorder(emitter)
+
+	emitter.EmitSync("a")
+	emitter.EmitSync("b")
+	emitter.EmitSync("c")
+
+	mock := &mockT{}
+	assert.True(t, AssertOrder(mock, rec, "a", "c"))
+	assert.True(t, AssertOrder(mock, rec, "a", "b", "c"))
+	assert.Empty(t, mock.errors)
+
+	assert.False(t, AssertOrder(mock, rec, "c", "a"))
+	assert.Equal(t, 1, len(mock.errors))
+}
+
+func TestEventuallyEmitted(t *testing.T) {
+	emitter := eventemitter.New()
+	rec := NewRecorder(emitter)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		emitter.EmitSync("event")
+	}()
+
+	mock := &mockT{}
+	assert.True(t, EventuallyEmitted(mock, rec, "event", time.Second))
+	assert.False(t, EventuallyEmitted(mock, rec, "missing", 10*time.Millisecond))
+	if assert.Equal(t, 1, len(mock.errors)) {
+		assert.Equal(t, "Event missing was not emitted within 10ms.\nEmitted: event[]", mock.errors[0])
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Package eventemittertest provides utilities for testing code using an event
+emitter.
+*/
+package eventemittertest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/attilabuti/eventemitter/v2"
+)
+
+// Event is a recorded emit.
+type Event struct {
+	Name      string
+	Arguments []any
+	Time      time.Time
+}
+
+// Recorder records the emits of an emitter, including the emits of events
+// without listeners.
+type Recorder struct {
+	mu      sync.Mutex
+	events  []Event
+	changed chan struct{} // Closed and replaced when an emit is recorded.
+	remove  func()
+}
+
+// NewRecorder returns a recorder attached to the emitter.
+func NewRecorder(emitter *eventemitter.Emitter) *Recorder {
+	r := &Recorder{changed: make(chan struct{})}
+	r.remove = emitter.Observe(r.record)
+
+	return r
+}
+
+// Events returns the recorded emits, in the order they were emitted.
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Event(nil), r.events...)
+}
+
+// Event returns the recorded emits of the event, in the order they were
+// emitted.
+func (r *Recorder) Event(name string) []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var events []Event
+	for _, event := range r.events {
+		if event.Name == name {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// Reset removes the recorded emits.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = nil
+}
+
+// Stop detaches the recorder from the emitter.
+func (r *Recorder) Stop() {
+	r.remove()
+}
+
+func (r *Recorder) record(eventName string, arguments []any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, Event{
+		Name:      eventName,
+		Arguments: append([]any(nil), arguments...),
+		Time:      time.Now(),
+	})
+
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// wait returns a channel that is closed when the next emit is recorded.
+func (r *Recorder) wait() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.changed
+}
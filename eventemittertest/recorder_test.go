@@ -0,0 +1,33 @@
+package eventemittertest
+
+import (
+	"testing"
+
+	"github.com/attilabuti/eventemitter/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	emitter := eventemitter.New()
+	rec := NewRecorder(emitter)
+
+	emitter.On("event", func(a int) {})
+	emitter.EmitSync("event", 1)
+	emitter.EmitSync("missing", "test")
+
+	events := rec.Events()
+	if assert.Equal(t, 2, len(events)) {
+		assert.Equal(t, "event", events[0].Name)
+		assert.Equal(t, []any{1}, events[0].Arguments)
+		assert.Equal(t, "missing", events[1].Name)
+		assert.False(t, events[0].Time.IsZero())
+	}
+	assert.Equal(t, 1, len(rec.Event("missing")))
+
+	rec.Reset()
+	assert.Empty(t, rec.Events())
+
+	rec.Stop()
+	emitter.EmitSync("event", 2)
+	assert.Empty(t, rec.Events())
+}
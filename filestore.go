@@ -0,0 +1,384 @@
+package eventemitter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+var (
+	ErrStoreClosed = errors.New("Store is closed")
+	ErrStoreCodec  = errors.New("Store was written with a codec")
+)
+
+// FileStore is a Store backed by an append-only log file. Every change is
+// synced to the file before it returns. The log is compacted when the store
+// is opened, and once enough events were completed.
+// Arguments are encoded with encoding/gob, so argument types other than the
+// basic ones must be registered with gob.Register, unless the store is opened
+// with an event codec.
+type FileStore struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	codec   *EventCodec
+	nextID  uint64
+	records map[uint64]*Record
+
+	threshold int // Completed events compacting the log.
+	completed int // Events completed since the last compaction.
+}
+
+// FileStoreOption configures a FileStore.
+type FileStoreOption func(*FileStore)
+
+type logOp uint8
+
+const (
+	logAppend logOp = iota + 1
+	logAck
+	logComplete
+)
+
+// logEntry is a change of the store, written to the log as a length-prefixed
+// gob encoding.
+type logEntry struct {
+	Op       logOp
+	Record   *Record
+	ID       uint64
+	Listener string
+	Event    []byte // The event encoded by the codec of the store, if any.
+}
+
+// WithStoreCodec encodes the events with the codec, so their arguments are
+// decoded into their registered types.
+func WithStoreCodec(codec *EventCodec) FileStoreOption {
+	return func(s *FileStore) {
+		s.codec = codec
+	}
+}
+
+// WithStoreCompaction compacts the log once threshold events were completed
+// since the last compaction. Defaults to 1000. The log is compacted only when
+// the store is opened if the threshold is less than or equal to zero.
+func WithStoreCompaction(threshold int) FileStoreOption {
+	return func(s *FileStore) {
+		s.threshold = threshold
+	}
+}
+
+// OpenFileStore opens the store at path, creating it if it does not exist.
+func OpenFileStore(path string, options ...FileStoreOption) (*FileStore, error) {
+	s := &FileStore{
+		path:      path,
+		nextID:    1,
+		records:   make(map[uint64]*Record),
+		threshold: 1000,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Append persists an event and returns its ID.
+func (s *FileStore) Append(eventName string, arguments []any) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record := &Record{ID: s.nextID, Event: eventName, Arguments: arguments}
+	if err := s.write(logEntry{Op: logAppend, Record: record}); err != nil {
+		return 0, err
+	}
+
+	s.nextID++
+	s.records[record.ID] = record
+
+	return record.ID, nil
+}
+
+// Ack records that the listener handled the event.
+func (s *FileStore) Ack(id uint64, listenerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[id]
+	if !ok {
+		return nil
+	}
+
+	if err := s.write(logEntry{Op: logAck, ID: id, Listener: listenerID}); err != nil {
+		return err
+	}
+
+	record.Acked = append(record.Acked, listenerID)
+
+	return nil
+}
+
+// Complete discards the event.
+func (s *FileStore) Complete(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[id]; !ok {
+		return nil
+	}
+
+	if err := s.write(logEntry{Op: logComplete, ID: id}); err != nil {
+		return err
+	}
+
+	delete(s.records, id)
+
+	if s.completed++; s.threshold > 0 && s.completed >= s.threshold {
+		return s.compact()
+	}
+
+	return nil
+}
+
+// Pending returns the events that are not completed, in the order they were
+// appended.
+func (s *FileStore) Pending() ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil, ErrStoreClosed
+	}
+
+	return s.pending(), nil
+}
+
+// pending returns a copy of the pending events. Must be called with s.mu held.
+func (s *FileStore) pending() []Record {
+	records := make([]Record, 0, len(s.records))
+	for _, record := range s.records {
+		r := *record
+		r.Acked = append([]string(nil), record.Acked...)
+		records = append(records, r)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].ID < records[j].ID
+	})
+
+	return records
+}
+
+// Close closes the log file.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return ErrStoreClosed
+	}
+
+	err := s.file.Close()
+	s.file = nil
+
+	return err
+}
+
+// load reads the log. An incomplete entry at the end of the log, left by a
+// crash during a write, is ignored.
+func (s *FileStore) load() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	for {
+		entry, err := readLogEntry(r)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		} else if err != nil {
+			// Only the last entry can be partially written.
+			if _, peekErr := r.Peek(1); peekErr == io.EOF {
+				return nil
+			}
+
+			return err
+		}
+
+		if err := s.decode(&entry); err != nil {
+			return err
+		}
+
+		s.apply(entry)
+	}
+}
+
+func (s *FileStore) apply(entry logEntry) {
+	switch entry.Op {
+	case logAppend:
+		s.records[entry.Record.ID] = entry.Record
+		if entry.Record.ID >= s.nextID {
+			s.nextID = entry.Record.ID + 1
+		}
+	case logAck:
+		if record, ok := s.records[entry.ID]; ok {
+			record.Acked = append(record.Acked, entry.Listener)
+		}
+	case logComplete:
+		delete(s.records, entry.ID)
+	}
+}
+
+// compact rewrites the log with the pending events only, and opens it for
+// appending. The current log is kept if it cannot be rewritten. Must be called
+// with s.mu held, or before the store is returned.
+func (s *FileStore) compact() error {
+	tmp := s.path + ".tmp"
+
+	var log bytes.Buffer
+	for _, record := range s.pending() {
+		r := record
+		if err := s.frame(&log, logEntry{Op: logAppend, Record: &r}); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.Write(log.Bytes()); err == nil {
+		err = file.Sync()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp, s.path)
+	}
+
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if s.file != nil {
+		s.file.Close()
+	}
+
+	s.completed = 0
+	s.file, err = os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0o600)
+
+	return err
+}
+
+func (s *FileStore) write(entry logEntry) error {
+	if s.file == nil {
+		return ErrStoreClosed
+	}
+
+	var frame bytes.Buffer
+	if err := s.frame(&frame, entry); err != nil {
+		return err
+	}
+
+	if _, err := s.file.Write(frame.Bytes()); err != nil {
+		return err
+	}
+
+	return s.file.Sync()
+}
+
+// frame appends the entry to the buffer, prefixed with its length.
+func (s *FileStore) frame(buf *bytes.Buffer, entry logEntry) error {
+	if err := s.encode(&entry); err != nil {
+		return err
+	}
+
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(entry); err != nil {
+		return err
+	}
+
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(payload.Len()))
+	buf.Write(size[:])
+	buf.Write(payload.Bytes())
+
+	return nil
+}
+
+// encode encodes the event of the entry with the codec of the store.
+func (s *FileStore) encode(entry *logEntry) error {
+	if s.codec == nil || entry.Record == nil {
+		return nil
+	}
+
+	event, err := s.codec.Marshal(entry.Record.Event, entry.Record.Arguments)
+	if err != nil {
+		return err
+	}
+
+	record := *entry.Record
+	record.Event = ""
+	record.Arguments = nil
+	entry.Record = &record
+	entry.Event = event
+
+	return nil
+}
+
+// decode decodes the event of the entry encoded with a codec.
+func (s *FileStore) decode(entry *logEntry) error {
+	if entry.Event == nil || entry.Record == nil {
+		return nil
+	}
+
+	if s.codec == nil {
+		return ErrStoreCodec
+	}
+
+	var err error
+	entry.Record.Event, entry.Record.Arguments, err = s.codec.Decode(entry.Event)
+
+	return err
+}
+
+func readLogEntry(r io.Reader) (logEntry, error) {
+	var entry logEntry
+
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return entry, err
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(size[:]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return entry, err
+	}
+
+	err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&entry)
+
+	return entry, err
+}
@@ -0,0 +1,160 @@
+package eventemitter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	store, err := OpenFileStore(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	id_1, err := store.Append("event_1", []any{1, "test", true})
+	assert.NoError(t, err)
+	id_2, err := store.Append("event_2", nil)
+	assert.NoError(t, err)
+	id_3, err := store.Append("event_3", []any{[]string{"t", "e", "s", "t"}})
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.Ack(id_1, "listener_1"))
+	assert.NoError(t, store.Ack(id_1, "listener_2"))
+	assert.NoError(t, store.Complete(id_2))
+
+	// Unknown events are ignored.
+	assert.NoError(t, store.Ack(100, "listener_1"))
+	assert.NoError(t, store.Complete(100))
+
+	expected := []Record{
+		{ID: id_1, Event: "event_1", Arguments: []any{1, "test", true}, Acked: []string{"listener_1", "listener_2"}},
+		{ID: id_3, Event: "event_3", Arguments: []any{[]string{"t", "e", "s", "t"}}},
+	}
+
+	records, err := store.Pending()
+	if assert.NoError(t, err) {
+		assert.Equal(t, expected, records)
+	}
+	assert.NoError(t, store.Close())
+
+	// Closed store.
+	_, err = store.Append("event", nil)
+	assert.Equal(t, ErrStoreClosed, err)
+	_, err = store.Pending()
+	assert.Equal(t, ErrStoreClosed, err)
+	assert.Equal(t, ErrStoreClosed, store.Close())
+
+	// Reopen the store, with a partially written entry at the end.
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if assert.NoError(t, err) {
+		file.Write([]byte{0, 0, 1, 0, 42})
+		file.Close()
+	}
+
+	store, err = OpenFileStore(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer store.Close()
+
+	records, err = store.Pending()
+	if assert.NoError(t, err) {
+		assert.Equal(t, expected, records)
+	}
+
+	id_4, err := store.Append("event_4", nil)
+	assert.NoError(t, err)
+	assert.True(t, id_4 > id_3)
+}
+
+func TestFileStoreCodec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	registry := NewRegistry()
+	registry.Register("order", codecOrder{})
+	codec := NewEventCodec(JSONCodec, registry)
+
+	store, err := OpenFileStore(path, WithStoreCodec(codec))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	id, err := store.Append("order", []any{codecOrder{ID: 1, Items: []string{"a"}}})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Ack(id, "listener_1"))
+
+	_, err = store.Append("order", []any{1})
+	assert.Error(t, err)
+	assert.NoError(t, store.Close())
+
+	// Logs written with a codec are read with a codec.
+	_, err = OpenFileStore(path)
+	assert.Equal(t, ErrStoreCodec, err)
+
+	store, err = OpenFileStore(path, WithStoreCodec(codec))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer store.Close()
+
+	records, err := store.Pending()
+	assert.NoError(t, err)
+	assert.Equal(t, []Record{{
+		ID:        id,
+		Event:     "order",
+		Arguments: []any{codecOrder{ID: 1, Items: []string{"a"}}},
+		Acked:     []string{"listener_1"},
+	}}, records)
+}
+
+func TestFileStoreCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	store, err := OpenFileStore(path, WithStoreCompaction(2))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer store.Close()
+
+	entries := func() int {
+		file, err := os.Open(path)
+		if !assert.NoError(t, err) {
+			return 0
+		}
+		defer file.Close()
+
+		n := 0
+		for {
+			if _, err := readLogEntry(file); err != nil {
+				return n
+			}
+			n++
+		}
+	}
+
+	id_1, _ := store.Append("event_1", nil)
+	id_2, _ := store.Append("event_2", nil)
+	store.Append("event_3", []any{3})
+	assert.NoError(t, store.Ack(id_1, "listener"))
+	assert.NoError(t, store.Complete(id_1))
+	assert.Equal(t, 5, entries())
+
+	// The log is compacted once two events were completed.
+	assert.NoError(t, store.Complete(id_2))
+	assert.Equal(t, 1, entries())
+
+	id_4, err := store.Append("event_4", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, entries())
+
+	records, err := store.Pending()
+	if assert.NoError(t, err) && assert.Equal(t, 2, len(records)) {
+		assert.Equal(t, "event_3", records[0].Event)
+		assert.Equal(t, id_4, records[1].ID)
+	}
+}
@@ -0,0 +1,36 @@
+package eventemitter
+
+// WithFilter calls the listener only for the emits whose arguments satisfy the
+// predicate. The predicate is called before the arguments are checked, and
+// before the asynchronous listener is started. Several filters can be given;
+// the listener is called if all of them are satisfied.
+func WithFilter(predicate func(args ...any) bool) ListenerOption {
+	return func(h *handler) {
+		if next := h.filter; next != nil {
+			h.filter = func(args ...any) bool {
+				return next(args...) && predicate(args...)
+			}
+		} else {
+			h.filter = predicate
+		}
+	}
+}
+
+// WithTypedFilter is a typed WithFilter over the first argument of the emits.
+// The listener is not called for the emits whose first argument is not a T.
+func WithTypedFilter[T any](predicate func(arg T) bool) ListenerOption {
+	return WithFilter(func(args ...any) bool {
+		if len(args) == 0 {
+			return false
+		}
+
+		arg, ok := args[0].(T)
+
+		return ok && predicate(arg)
+	})
+}
+
+// filtered reports whether the listener is filtered out for the arguments.
+func (h *handler) filtered(arguments []any) bool {
+	return h.filter != nil && !h.filter(arguments...)
+}
@@ -0,0 +1,52 @@
+package eventemitter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type order struct {
+	ID     int
+	Status string
+}
+
+func TestFilter(t *testing.T) {
+	emitter := New()
+
+	var received []int
+	emitter.On("order.updated", func(id int, status string) {
+		received = append(received, id)
+	}, WithFilter(func(args ...any) bool {
+		return args[1] == "shipped"
+	}), WithFilter(func(args ...any) bool {
+		return args[0] != 3
+	}))
+
+	emitter.EmitSync("order.updated", 1, "pending")
+	emitter.EmitSync("order.updated", 2, "shipped")
+	emitter.EmitSync("order.updated", 3, "shipped")
+	assert.Equal(t, []int{2}, received)
+
+	// Filtered out listeners don't check the arguments.
+	assert.NotPanics(t, func() {
+		emitter.EmitSync("order.updated", "4", "pending")
+	})
+}
+
+func TestTypedFilter(t *testing.T) {
+	emitter := New()
+
+	var received []order
+	emitter.On("order.updated", func(o order) {
+		received = append(received, o)
+	}, WithTypedFilter(func(o order) bool {
+		return o.Status == "shipped"
+	}))
+
+	emitter.EmitSync("order.updated", order{1, "pending"})
+	emitter.EmitSync("order.updated", order{2, "shipped"})
+	emitter.EmitSync("order.updated", "shipped")
+	emitter.EmitSync("order.updated")
+	assert.Equal(t, []order{{2, "shipped"}}, received)
+}
@@ -0,0 +1,200 @@
+package eventemitter
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// ListenerOption configures a listener when it is added to an event.
+type ListenerOption func(*handler)
+
+// fastKind identifies the common listener signatures, which are called
+// without reflection.
+type fastKind uint8
+
+const (
+	fastNone        fastKind = iota
+	fastNoArgs               // func()
+	fastAny                  // func(any)
+	fastVariadicAny          // func(...any)
+	fastString               // func(string)
+	fastInt                  // func(int)
+	fastBool                 // func(bool)
+)
+
+// handler is a registered listener along with its options and the cached
+// metadata of its signature.
+type handler struct {
+	fn         any
+	id         string
+	group      string
+	registered time.Time
+	timeout    time.Duration
+	retry      *RetryPolicy
+	replay     bool
+	coalescer  *coalescer
+	filter     func(args ...any) bool
+
+	value       reflect.Value // The function, or the pointer to the function.
+	fnType      reflect.Type  // The type of the function.
+	pointer     bool          // The listener is a pointer to a function.
+	context     bool          // The first parameter is a context.Context.
+	emitContext bool          // The context of the emit is always passed.
+	errors      bool          // The last result is an error.
+	kind        fastKind
+}
+
+// WithGroup assigns the listener to the specified group, so it can be removed
+// together with the other listeners of the group using .RemoveGroup(group).
+func WithGroup(group string) ListenerOption {
+	return func(h *handler) {
+		h.group = group
+	}
+}
+
+// WithEmitContext always passes the context of the emit to the listener,
+// whose first parameter must be a context.Context. By default, a context passed
+// as the first argument of an emit is received instead, e.g. by listeners
+// written before the emits passed a context.
+func WithEmitContext() ListenerOption {
+	return func(h *handler) {
+		h.emitContext = true
+	}
+}
+
+func newHandler(fn any, options []ListenerOption) *handler {
+	h := &handler{fn: fn, registered: time.Now()}
+	for _, option := range options {
+		option(h)
+	}
+
+	if h.coalescer != nil && h.coalescer.wait <= 0 && h.coalescer.size <= 0 {
+		h.coalescer = nil
+	}
+
+	h.value = reflect.ValueOf(fn)
+	h.fnType = h.value.Type()
+	if h.fnType.Kind() == reflect.Pointer {
+		h.pointer = true
+		h.fnType = h.fnType.Elem()
+	}
+
+	h.context = h.fnType.NumIn() > 0 && h.fnType.In(0) == contextType
+	h.errors = h.fnType.NumOut() > 0 && h.fnType.Out(h.fnType.NumOut()-1) == errorType
+	h.kind = fastKindOf(fn)
+
+	return h
+}
+
+func fastKindOf(fn any) fastKind {
+	switch fn.(type) {
+	case func(), *func():
+		return fastNoArgs
+	case func(any), *func(any):
+		return fastAny
+	case func(...any), *func(...any):
+		return fastVariadicAny
+	case func(string), *func(string):
+		return fastString
+	case func(int), *func(int):
+		return fastInt
+	case func(bool), *func(bool):
+		return fastBool
+	}
+
+	return fastNone
+}
+
+// function returns the function of the listener. Pointers are dereferenced on
+// every call, so the listener sees reassignments of the pointed function.
+func (h *handler) function() reflect.Value {
+	if h.pointer {
+		return h.value.Elem()
+	}
+
+	return h.value
+}
+
+// accepts reports whether the listener can be called with the arguments
+// without reflection.
+func (h *handler) accepts(args []any) bool {
+	switch h.kind {
+	case fastNoArgs:
+		return len(args) == 0
+	case fastAny:
+		return len(args) == 1
+	case fastVariadicAny:
+		return true
+	case fastString:
+		if len(args) == 1 {
+			_, ok := args[0].(string)
+			return ok
+		}
+	case fastInt:
+		if len(args) == 1 {
+			_, ok := args[0].(int)
+			return ok
+		}
+	case fastBool:
+		if len(args) == 1 {
+			_, ok := args[0].(bool)
+			return ok
+		}
+	}
+
+	return false
+}
+
+// fastCall calls the listener without reflection. The arguments must be
+// accepted by the listener.
+func (h *handler) fastCall(args []any) {
+	switch fn := h.fn.(type) {
+	case func():
+		fn()
+	case *func():
+		(*fn)()
+	case func(any):
+		fn(args[0])
+	case *func(any):
+		(*fn)(args[0])
+	case func(...any):
+		fn(args...)
+	case *func(...any):
+		(*fn)(args...)
+	case func(string):
+		fn(args[0].(string))
+	case *func(string):
+		(*fn)(args[0].(string))
+	case func(int):
+		fn(args[0].(int))
+	case *func(int):
+		(*fn)(args[0].(int))
+	case func(bool):
+		fn(args[0].(bool))
+	case *func(bool):
+		(*fn)(args[0].(bool))
+	}
+}
+
+// call calls the listener. Returns the error returned by the listener, if any.
+func (inv *invocation) call(ctx context.Context) error {
+	if inv.fast {
+		inv.listener.fastCall(inv.arguments)
+		return nil
+	}
+
+	args := inv.args
+	if inv.withContext {
+		args = append([]reflect.Value{reflect.ValueOf(ctx)}, args...)
+	}
+
+	results := inv.listener.function().Call(args)
+	if inv.listener.errors {
+		if err, ok := results[len(results)-1].Interface().(error); ok {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package eventemitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// History records the last emits of an emitter, for debugging and tests.
+type History struct {
+	mu      sync.Mutex
+	size    int
+	entries []*HistoryEntry // Ring buffer of the entries.
+	next    int             // Index of the next entry in the ring buffer.
+}
+
+// HistoryEntry is a recorded emit.
+type HistoryEntry struct {
+	Event     string
+	Arguments []any
+	Time      time.Time
+	Err       error // Error of the emit, e.g. ErrEventNotExists.
+	Listeners []ListenerOutcome
+}
+
+// ListenerOutcome is the outcome of a listener call of a recorded emit.
+type ListenerOutcome struct {
+	Listener string        // Name of the listener function.
+	Done     bool          // The listener returned, or panicked.
+	Duration time.Duration // Duration of the call.
+	Err      error         // Error returned by the listener.
+	Panic    any           // Value passed to panic by the listener.
+}
+
+// historyOutcome is the outcome of a listener call to be recorded.
+type historyOutcome struct {
+	history *History
+	entry   *HistoryEntry
+	index   int
+}
+
+// NewHistory returns a history keeping the last size emits.
+func NewHistory(size int) *History {
+	if size <= 0 {
+		size = 1
+	}
+
+	return &History{size: size, entries: make([]*HistoryEntry, 0, size)}
+}
+
+// WithHistory records the emits of the emitter in the history.
+func WithHistory(history *History) Option {
+	return func(e *Emitter) {
+		e.history = history
+	}
+}
+
+// Entries returns the recorded emits, from the oldest to the newest.
+func (h *History) Entries() []HistoryEntry {
+	return h.filter(func(entry *HistoryEntry) bool {
+		return true
+	})
+}
+
+// Event returns the recorded emits of the event, from the oldest to the newest.
+func (h *History) Event(eventName string) []HistoryEntry {
+	return h.filter(func(entry *HistoryEntry) bool {
+		return entry.Event == eventName
+	})
+}
+
+// Between returns the emits recorded in the time range [from, to), from the
+// oldest to the newest.
+func (h *History) Between(from, to time.Time) []HistoryEntry {
+	return h.filter(func(entry *HistoryEntry) bool {
+		return !entry.Time.Before(from) && entry.Time.Before(to)
+	})
+}
+
+// Clear removes the recorded emits.
+func (h *History) Clear() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = h.entries[:0]
+	h.next = 0
+}
+
+// MarshalJSON encodes the recorded emits as a JSON array. Errors, panics, and
+// arguments that cannot be encoded are encoded as strings.
+func (h *History) MarshalJSON() ([]byte, error) {
+	type outcome struct {
+		Listener string        `json:"listener"`
+		Done     bool          `json:"done"`
+		Duration time.Duration `json:"duration"`
+		Err      string        `json:"error,omitempty"`
+		Panic    any           `json:"panic,omitempty"`
+	}
+
+	type entry struct {
+		Event     string            `json:"event"`
+		Arguments []json.RawMessage `json:"arguments"`
+		Time      time.Time         `json:"time"`
+		Err       string            `json:"error,omitempty"`
+		Listeners []outcome         `json:"listeners"`
+	}
+
+	entries := h.Entries()
+	encoded := make([]entry, 0, len(entries))
+	for _, e := range entries {
+		en := entry{
+			Event:     e.Event,
+			Arguments: make([]json.RawMessage, 0, len(e.Arguments)),
+			Time:      e.Time,
+			Listeners: make([]outcome, 0, len(e.Listeners)),
+		}
+		if e.Err != nil {
+			en.Err = e.Err.Error()
+		}
+
+		for _, arg := range e.Arguments {
+			en.Arguments = append(en.Arguments, encodeArgument(arg))
+		}
+
+		for _, l := range e.Listeners {
+			o := outcome{Listener: l.Listener, Done: l.Done, Duration: l.Duration}
+			if l.Err != nil {
+				o.Err = l.Err.Error()
+			}
+			if l.Panic != nil {
+				o.Panic = fmt.Sprint(l.Panic)
+			}
+			en.Listeners = append(en.Listeners, o)
+		}
+
+		encoded = append(encoded, en)
+	}
+
+	return json.Marshal(encoded)
+}
+
+// record adds an emit to the history, and returns its entry.
+func (h *History) record(eventName string, arguments []any, listeners []*handler, err error) *HistoryEntry {
+	entry := &HistoryEntry{
+		Event:     eventName,
+		Arguments: append([]any(nil), arguments...),
+		Time:      time.Now(),
+		Err:       err,
+		Listeners: make([]ListenerOutcome, len(listeners)),
+	}
+
+	for i, listener := range listeners {
+		entry.Listeners[i].Listener = listener.info().Function
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.entries) < h.size {
+		h.entries = append(h.entries, entry)
+	} else {
+		h.entries[h.next] = entry
+	}
+	h.next = (h.next + 1) % h.size
+
+	return entry
+}
+
+func (h *History) filter(match func(entry *HistoryEntry) bool) []HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	start := 0
+	if len(h.entries) == h.size {
+		start = h.next
+	}
+
+	var entries []HistoryEntry
+	for i := 0; i < len(h.entries); i++ {
+		entry := h.entries[(start+i)%len(h.entries)]
+		if match(entry) {
+			e := *entry
+			e.Listeners = append([]ListenerOutcome(nil), entry.Listeners...)
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+func (o *historyOutcome) done(duration time.Duration, err error, panicked any) {
+	o.history.mu.Lock()
+	defer o.history.mu.Unlock()
+
+	outcome := &o.entry.Listeners[o.index]
+	outcome.Done = true
+	outcome.Duration = duration
+	outcome.Err = err
+	outcome.Panic = panicked
+}
+
+// encodeArgument encodes an argument of a recorded emit, or its string
+// representation if it cannot be encoded.
+func encodeArgument(arg any) json.RawMessage {
+	data, err := json.Marshal(arg)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(arg))
+	}
+
+	return data
+}
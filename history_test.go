@@ -0,0 +1,139 @@
+package eventemitter
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	history := NewHistory(3)
+	emitter := New(WithHistory(history))
+
+	errListener := errors.New("listener")
+	emitter.On("event", func(a int) {})
+	emitter.On("event", func(a int) error { return errListener })
+	emitter.On("panic", func() { panic("listener") })
+
+	start := time.Now()
+	emitter.EmitSync("event", 1)
+	emitter.EmitSync("missing", "test")
+	assert.Panics(t, func() { emitter.EmitSync("panic") })
+	middle := time.Now()
+	emitter.EmitSync("event", 2)
+
+	// The oldest emit is dropped.
+	entries := history.Entries()
+	if assert.Equal(t, 3, len(entries)) {
+		assert.Equal(t, "missing", entries[0].Event)
+		assert.Equal(t, []any{"test"}, entries[0].Arguments)
+		assert.Equal(t, ErrEventNotExists, entries[0].Err)
+		assert.Equal(t, 0, len(entries[0].Listeners))
+
+		assert.Equal(t, "panic", entries[1].Event)
+		if assert.Equal(t, 1, len(entries[1].Listeners)) {
+			assert.True(t, entries[1].Listeners[0].Done)
+			assert.Equal(t, "listener", entries[1].Listeners[0].Panic)
+		}
+
+		assert.Equal(t, "event", entries[2].Event)
+		assert.Equal(t, []any{2}, entries[2].Arguments)
+		assert.False(t, entries[2].Time.Before(middle))
+		if assert.Equal(t, 2, len(entries[2].Listeners)) {
+			outcome := entries[2].Listeners[0]
+			assert.Equal(t, "github.com/attilabuti/eventemitter/v2.TestHistory.func1", outcome.Listener)
+			assert.True(t, outcome.Done)
+			assert.NoError(t, outcome.Err)
+			assert.Nil(t, outcome.Panic)
+			assert.Equal(t, errListener, entries[2].Listeners[1].Err)
+		}
+	}
+
+	// Query by event name and time range.
+	assert.Equal(t, 1, len(history.Event("event")))
+	assert.Equal(t, 0, len(history.Event("other")))
+	assert.Equal(t, 2, len(history.Between(start, middle)))
+	assert.Equal(t, 1, len(history.Between(middle, time.Now().Add(time.Second))))
+
+	// Clear the history.
+	history.Clear()
+	assert.Equal(t, 0, len(history.Entries()))
+	emitter.EmitSync("event", 3)
+	assert.Equal(t, 1, len(history.Entries()))
+}
+
+func TestHistoryAsync(t *testing.T) {
+	history := NewHistory(10)
+	emitter := New(WithHistory(history))
+
+	release := make(chan struct{})
+	emitter.On("event", func() {
+		<-release
+	})
+
+	emitter.Emit("event")
+	entries := history.Entries()
+	if assert.Equal(t, 1, len(entries)) {
+		assert.False(t, entries[0].Listeners[0].Done)
+	}
+
+	close(release)
+	emitter.Close()
+
+	assert.True(t, history.Entries()[0].Listeners[0].Done)
+}
+
+func TestHistoryJSON(t *testing.T) {
+	history := NewHistory(10)
+	emitter := New(WithHistory(history))
+
+	emitter.On("event", func(a int, b string) error { return errors.New("listener") })
+	emitter.EmitSync("event", 1, "test")
+	emitter.EmitSync("missing")
+
+	data, err := json.Marshal(history)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var entries []map[string]any
+	if assert.NoError(t, json.Unmarshal(data, &entries)) && assert.Equal(t, 2, len(entries)) {
+		assert.Equal(t, "event", entries[0]["event"])
+		assert.Equal(t, []any{1.0, "test"}, entries[0]["arguments"])
+		assert.Nil(t, entries[0]["error"])
+
+		listeners := entries[0]["listeners"].([]any)
+		if assert.Equal(t, 1, len(listeners)) {
+			assert.Equal(t, "listener", listeners[0].(map[string]any)["error"])
+			assert.Equal(t, true, listeners[0].(map[string]any)["done"])
+		}
+
+		assert.Equal(t, ErrEventNotExists.Error(), entries[1]["error"])
+	}
+
+	// Arguments that cannot be encoded.
+	history.Clear()
+	emitter.On("unencodable", func(args ...any) {})
+	emitter.EmitSync("unencodable", make(chan int), func() {}, math.NaN(), struct{ F func() }{}, 1)
+
+	data, err = json.Marshal(history)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	entries = nil
+	if assert.NoError(t, json.Unmarshal(data, &entries)) && assert.Equal(t, 1, len(entries)) {
+		arguments := entries[0]["arguments"].([]any)
+		if assert.Equal(t, 5, len(arguments)) {
+			assert.IsType(t, "", arguments[0])
+			assert.IsType(t, "", arguments[1])
+			assert.Equal(t, "NaN", arguments[2])
+			assert.IsType(t, "", arguments[3])
+			assert.Equal(t, 1.0, arguments[4])
+		}
+	}
+}
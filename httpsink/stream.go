@@ -0,0 +1,212 @@
+/*
+Package httpsink exposes the events of an emitter over HTTP, as a stream of
+Server-Sent Events, and delivers them to webhooks.
+*/
+package httpsink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/attilabuti/eventemitter/v2"
+)
+
+// Stream is an http.Handler streaming the emits of an emitter to the clients
+// as Server-Sent Events. The name of the event is the SSE event type, and the
+// data is the JSON array of its arguments. Clients can select events with
+// event query parameters, e.g. /events?event=order.created&event=order.updated.
+type Stream struct {
+	options streamOptions
+	remove  func()
+
+	mu      sync.Mutex
+	clients map[*client]struct{}
+	closed  bool
+	done    chan struct{}
+}
+
+// StreamOption configures a stream.
+type StreamOption func(*streamOptions)
+
+type streamOptions struct {
+	events    map[string]bool
+	buffer    int
+	heartbeat time.Duration
+}
+
+// client is a connected client. Emits are dropped while its buffer is full, so
+// slow clients don't block the emits.
+type client struct {
+	events map[string]bool
+	send   chan []byte
+}
+
+// WithEvents streams the events named eventNames only. All events are streamed
+// by default.
+func WithEvents(eventNames ...string) StreamOption {
+	return func(o *streamOptions) {
+		if o.events == nil {
+			o.events = make(map[string]bool, len(eventNames))
+		}
+
+		for _, eventName := range eventNames {
+			o.events[eventName] = true
+		}
+	}
+}
+
+// WithBuffer sets the number of events buffered for each client. Defaults to
+// 64. Events are dropped for the clients whose buffer is full.
+func WithBuffer(size int) StreamOption {
+	return func(o *streamOptions) {
+		o.buffer = size
+	}
+}
+
+// WithHeartbeat sends a comment to the clients at the interval, so idle
+// connections are kept open by proxies. Disabled by default.
+func WithHeartbeat(interval time.Duration) StreamOption {
+	return func(o *streamOptions) {
+		o.heartbeat = interval
+	}
+}
+
+// NewStream returns a stream of the emits of the emitter.
+func NewStream(emitter *eventemitter.Emitter, options ...StreamOption) *Stream {
+	s := &Stream{
+		options: streamOptions{buffer: 64},
+		clients: make(map[*client]struct{}),
+		done:    make(chan struct{}),
+	}
+
+	for _, option := range options {
+		option(&s.options)
+	}
+
+	if s.options.buffer < 1 {
+		s.options.buffer = 1
+	}
+
+	s.remove = emitter.Observe(s.observe)
+
+	return s
+}
+
+// ServeHTTP streams the events to the client until it disconnects or the
+// stream is closed.
+func (s *Stream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	c := &client{send: make(chan []byte, s.options.buffer)}
+	if eventNames := r.URL.Query()["event"]; len(eventNames) != 0 {
+		c.events = make(map[string]bool, len(eventNames))
+		for _, eventName := range eventNames {
+			c.events[eventName] = true
+		}
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		http.Error(w, "Stream is closed", http.StatusServiceUnavailable)
+		return
+	}
+	s.clients[c] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, c)
+		s.mu.Unlock()
+	}()
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	var heartbeat <-chan time.Time
+	if s.options.heartbeat > 0 {
+		ticker := time.NewTicker(s.options.heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case message := <-c.send:
+			if _, err := w.Write(message); err != nil {
+				return
+			}
+		case <-heartbeat:
+			if _, err := w.Write([]byte(": heartbeat\n\n")); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		}
+
+		flusher.Flush()
+	}
+}
+
+// Close stops observing the emitter, and ends the streams of the clients.
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+		s.remove()
+	}
+
+	return nil
+}
+
+func (s *Stream) observe(eventName string, arguments []any) {
+	if s.options.events != nil && !s.options.events[eventName] {
+		return
+	}
+
+	var message []byte
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.clients {
+		if c.events != nil && !c.events[eventName] {
+			continue
+		}
+
+		// The message is encoded once, for the first client receiving it.
+		if message == nil {
+			if arguments == nil {
+				arguments = []any{}
+			}
+
+			data, err := json.Marshal(arguments)
+			if err != nil {
+				return
+			}
+
+			message = []byte(fmt.Sprintf("event: %s\ndata: %s\n\n", eventName, data))
+		}
+
+		select {
+		case c.send <- message:
+		default:
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package httpsink
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/attilabuti/eventemitter/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// readEvents reads n events from the SSE stream.
+func readEvents(t *testing.T, r *bufio.Reader, n int) []string {
+	t.Helper()
+
+	var events []string
+	var event []string
+	for len(events) < n {
+		line, err := r.ReadString('\n')
+		if !assert.NoError(t, err) {
+			return events
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			events = append(events, strings.Join(event, "|"))
+			event = nil
+		} else {
+			event = append(event, line)
+		}
+	}
+
+	return events
+}
+
+// connect connects to the stream, and waits until the client is registered.
+func connect(t *testing.T, stream *Stream, url string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Eventually(t, func() bool {
+		stream.mu.Lock()
+		defer stream.mu.Unlock()
+
+		return len(stream.clients) != 0
+	}, time.Second, 5*time.Millisecond)
+
+	return resp, bufio.NewReader(resp.Body)
+}
+
+func TestStream(t *testing.T) {
+	emitter := eventemitter.New()
+	stream := NewStream(emitter, WithEvents("order.created", "order.updated"))
+	defer stream.Close()
+
+	server := httptest.NewServer(stream)
+	defer server.Close()
+
+	resp, r := connect(t, stream, server.URL)
+	defer resp.Body.Close()
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Events without listeners are streamed too.
+	emitter.EmitSync("order.created", "order-1", 100)
+	emitter.EmitSync("user.created", "alice")
+	emitter.EmitSync("order.updated", map[string]any{"status": "shipped"})
+
+	assert.Equal(t, []string{
+		`event: order.created|data: ["order-1",100]`,
+		`event: order.updated|data: [{"status":"shipped"}]`,
+	}, readEvents(t, r, 2))
+}
+
+func TestStreamQuery(t *testing.T) {
+	emitter := eventemitter.New()
+	stream := NewStream(emitter, WithHeartbeat(10*time.Millisecond))
+
+	server := httptest.NewServer(stream)
+	defer server.Close()
+
+	resp, r := connect(t, stream, server.URL+"?event=b&event=c")
+	defer resp.Body.Close()
+
+	emitter.EmitSync("a", 1)
+	emitter.EmitSync("b", 2)
+	emitter.EmitSync("c")
+
+	events := readEvents(t, r, 4)
+	var received []string
+	for _, event := range events {
+		if event != ": heartbeat" {
+			received = append(received, event)
+		}
+	}
+	assert.Subset(t, []string{"event: b|data: [2]", "event: c|data: []"}, received)
+	assert.Contains(t, events, ": heartbeat")
+
+	// Closing the stream ends the responses.
+	assert.NoError(t, stream.Close())
+	assert.Eventually(t, func() bool {
+		_, err := r.ReadString('\n')
+		return err != nil
+	}, time.Second, 5*time.Millisecond)
+
+	resp, err := http.Get(server.URL)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,206 @@
+package httpsink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/attilabuti/eventemitter/v2"
+)
+
+// SignatureHeader is the header of the HMAC-SHA256 signature of the payloads,
+// formatted as "sha256=<hex digest>".
+const SignatureHeader = "X-Signature-256"
+
+// Payload is the JSON body posted to a webhook.
+type Payload struct {
+	Event     string    `json:"event"`
+	Arguments []any     `json:"arguments"`
+	Time      time.Time `json:"time"`
+}
+
+// StatusError is returned when a webhook responds with a non-2xx status.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Webhook %s responded with status %d", e.URL, e.StatusCode)
+}
+
+// Webhook posts the emits of events to a URL.
+type Webhook struct {
+	url     string
+	options webhookOptions
+}
+
+// WebhookOption configures a webhook.
+type WebhookOption func(*webhookOptions)
+
+type webhookOptions struct {
+	secret []byte
+	client *http.Client
+	retry  eventemitter.RetryPolicy
+}
+
+// WithSecret signs the payloads with the secret, in the X-Signature-256 header.
+func WithSecret(secret []byte) WebhookOption {
+	return func(o *webhookOptions) {
+		o.secret = secret
+	}
+}
+
+// WithClient sets the HTTP client of the webhook. Defaults to a client with a
+// 10 seconds timeout.
+func WithClient(client *http.Client) WebhookOption {
+	return func(o *webhookOptions) {
+		o.client = client
+	}
+}
+
+// WithRetry sets the retry policy of the listeners added by Attach. Defaults to
+// 3 attempts with an exponential backoff from 500ms. Unless the policy sets
+// Retryable, the requests are retried on network errors, 429 and 5xx statuses.
+func WithRetry(policy eventemitter.RetryPolicy) WebhookOption {
+	return func(o *webhookOptions) {
+		o.retry = policy
+	}
+}
+
+// NewWebhook returns a webhook posting to the URL.
+func NewWebhook(url string, options ...WebhookOption) *Webhook {
+	w := &Webhook{
+		url: url,
+		options: webhookOptions{
+			client: &http.Client{Timeout: 10 * time.Second},
+			retry:  eventemitter.RetryPolicy{MaxAttempts: 3, Backoff: 500 * time.Millisecond},
+		},
+	}
+
+	for _, option := range options {
+		option(&w.options)
+	}
+
+	if w.options.retry.Retryable == nil {
+		w.options.retry.Retryable = retryable
+	}
+
+	return w
+}
+
+// Attach adds listeners posting the emits of the events named eventNames to
+// the webhook, with the retry policy of the webhook. Failed asynchronous posts
+// are passed to the dead letter hook of the emitter.
+// Returns a function that removes the listeners.
+func (w *Webhook) Attach(emitter *eventemitter.Emitter, eventNames ...string) (remove func(), err error) {
+	var listeners []*func(ctx context.Context, args ...any) error
+	remove = func() {
+		for i, listener := range listeners {
+			emitter.RemoveListener(eventNames[i], listener)
+		}
+	}
+
+	for _, eventName := range eventNames {
+		listener := w.Listener(eventName)
+		if err := emitter.AddListener(eventName, &listener, eventemitter.WithRetry(w.options.retry), eventemitter.WithEmitContext()); err != nil {
+			remove()
+			return nil, err
+		}
+
+		listeners = append(listeners, &listener)
+	}
+
+	return remove, nil
+}
+
+// Listener returns a listener posting the emits of the event to the webhook.
+// It must be added with the eventemitter.WithEmitContext option, so contexts
+// emitted as the first argument are posted as arguments.
+func (w *Webhook) Listener(eventName string) func(ctx context.Context, args ...any) error {
+	return func(ctx context.Context, args ...any) error {
+		return w.Post(ctx, eventName, args)
+	}
+}
+
+// Post posts the event to the webhook.
+func (w *Webhook) Post(ctx context.Context, eventName string, arguments []any) error {
+	if arguments == nil {
+		arguments = []any{}
+	}
+
+	body, err := json.Marshal(Payload{Event: eventName, Arguments: arguments, Time: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event", eventName)
+	if w.options.secret != nil {
+		req.Header.Set(SignatureHeader, Sign(w.options.secret, body))
+	}
+
+	resp, err := w.options.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// The body is drained, so the connection is reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{URL: w.url, StatusCode: resp.StatusCode}
+	}
+
+	return nil
+}
+
+// Sign returns the signature of the body, as set in the X-Signature-256 header.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether the signature of the body is valid, for the receivers
+// of the webhooks.
+func Verify(secret, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+
+	digest, err := hex.DecodeString(signature[len("sha256="):])
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+
+	return hmac.Equal(digest, mac.Sum(nil))
+}
+
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+
+	return true
+}
@@ -0,0 +1,109 @@
+package httpsink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/attilabuti/eventemitter/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook(t *testing.T) {
+	secret := []byte("secret")
+
+	payloads := make(chan Payload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !Verify(secret, body, r.Header.Get(SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var payload Payload
+		json.Unmarshal(body, &payload)
+		payloads <- payload
+	}))
+	defer server.Close()
+
+	emitter := eventemitter.New()
+	remove, err := NewWebhook(server.URL, WithSecret(secret)).Attach(emitter, "order.created")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, emitter.EmitSync("order.created", "order-1", 100))
+
+	payload := <-payloads
+	assert.Equal(t, "order.created", payload.Event)
+	assert.Equal(t, []any{"order-1", float64(100)}, payload.Arguments)
+	assert.WithinDuration(t, time.Now(), payload.Time, time.Minute)
+
+	// Invalid signatures are rejected, and not retried.
+	err = NewWebhook(server.URL, WithSecret([]byte("other"))).Post(context.Background(), "event", nil)
+	var statusErr *StatusError
+	if assert.True(t, errors.As(err, &statusErr)) {
+		assert.Equal(t, http.StatusUnauthorized, statusErr.StatusCode)
+	}
+
+	remove()
+	assert.Equal(t, eventemitter.ErrEventNotExists, emitter.EmitSync("order.created", "order-2", 100))
+}
+
+func TestWebhookRetry(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	emitter := eventemitter.New()
+	_, err := NewWebhook(server.URL, WithRetry(eventemitter.RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     time.Millisecond,
+	})).Attach(emitter, "event")
+	assert.NoError(t, err)
+
+	assert.NoError(t, emitter.EmitSync("event"))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// Client errors are not retried.
+	atomic.StoreInt32(&requests, 0)
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer failing.Close()
+
+	_, err = NewWebhook(failing.URL).Attach(emitter, "failing")
+	assert.NoError(t, err)
+
+	err = emitter.EmitSync("failing")
+	assert.EqualError(t, err, "Listeners of event failing failed: Webhook "+failing.URL+" responded with status 400")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestVerify(t *testing.T) {
+	secret := []byte("secret")
+	body := []byte(`{"event":"test"}`)
+
+	signature := Sign(secret, body)
+	assert.True(t, Verify(secret, body, signature))
+	assert.False(t, Verify([]byte("other"), body, signature))
+	assert.False(t, Verify(secret, []byte(`{}`), signature))
+	assert.False(t, Verify(secret, body, signature[len("sha256="):]))
+	assert.False(t, Verify(secret, body, "sha256=zz"))
+}
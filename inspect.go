@@ -0,0 +1,124 @@
+package eventemitter
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Inspection is a snapshot of the registered listeners, ordered by event name.
+type Inspection []EventInfo
+
+// EventInfo describes an event and its listeners, in the order they are called.
+type EventInfo struct {
+	Name      string
+	Listeners []ListenerInfo
+}
+
+// ListenerInfo describes a registered listener.
+type ListenerInfo struct {
+	Function   string    // Name of the function, e.g. "main.main.func1".
+	File       string    // Source file of the function.
+	Line       int       // Source line of the function.
+	Signature  string    // Type of the function, e.g. "func(string)".
+	Pointer    bool      // The listener was registered as a pointer to a function.
+	ID         string    // ID of the listener, if any.
+	Group      string    // Group of the listener, if any.
+	Registered time.Time // Time of the registration.
+}
+
+// Inspect returns a snapshot of the events and their listeners.
+func (e *Emitter) Inspect() Inspection {
+	var inspection Inspection
+
+	e.listeners.Range(func(eventName, value any) bool {
+		listeners := value.([]*handler)
+
+		info := EventInfo{
+			Name:      eventName.(string),
+			Listeners: make([]ListenerInfo, 0, len(listeners)),
+		}
+		for _, h := range listeners {
+			info.Listeners = append(info.Listeners, h.info())
+		}
+		inspection = append(inspection, info)
+
+		return true
+	})
+
+	sort.Slice(inspection, func(i, j int) bool {
+		return inspection[i].Name < inspection[j].Name
+	})
+
+	return inspection
+}
+
+// String returns the inspection in a human-readable form, one event per line
+// followed by its listeners.
+func (i Inspection) String() string {
+	var b strings.Builder
+
+	for _, event := range i {
+		fmt.Fprintf(&b, "%s (%d)\n", event.Name, len(event.Listeners))
+
+		for _, listener := range event.Listeners {
+			fmt.Fprintf(&b, "\t%s\n", listener)
+		}
+	}
+
+	return b.String()
+}
+
+// String returns the listener in a human-readable form.
+func (l ListenerInfo) String() string {
+	var b strings.Builder
+
+	if l.Pointer {
+		b.WriteString("*")
+	}
+	fmt.Fprintf(&b, "%s %s", l.Function, l.Signature)
+
+	if len(l.File) != 0 {
+		fmt.Fprintf(&b, " at %s:%d", l.File, l.Line)
+	}
+
+	if len(l.ID) != 0 {
+		fmt.Fprintf(&b, " id=%s", l.ID)
+	}
+
+	if len(l.Group) != 0 {
+		fmt.Fprintf(&b, " group=%s", l.Group)
+	}
+
+	fmt.Fprintf(&b, " registered=%s", l.Registered.Format(time.RFC3339Nano))
+
+	return b.String()
+}
+
+func (h *handler) info() ListenerInfo {
+	fn := reflect.ValueOf(h.fn)
+
+	info := ListenerInfo{
+		ID:         h.id,
+		Group:      h.group,
+		Registered: h.registered,
+	}
+
+	// If the listener is a pointer to a function, get the function.
+	if fn.Kind() == reflect.Pointer {
+		info.Pointer = true
+		fn = fn.Elem()
+	}
+
+	info.Signature = fn.Type().String()
+
+	if f := runtime.FuncForPC(fn.Pointer()); f != nil {
+		info.Function = f.Name()
+		info.File, info.Line = f.FileLine(f.Entry())
+	}
+
+	return info
+}
@@ -0,0 +1,56 @@
+package eventemitter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testInspectListener(name string) {}
+
+func TestInspect(t *testing.T) {
+	emitter := New()
+
+	start := time.Now()
+	event := func(a int, b string) {}
+
+	emitter.On("event_2", event)
+	emitter.On("event_2", &event, WithGroup("plugin"))
+	emitter.On("event_1", testInspectListener)
+
+	inspection := emitter.Inspect()
+	if assert.Equal(t, 2, len(inspection)) {
+		assert.Equal(t, "event_1", inspection[0].Name)
+		assert.Equal(t, "event_2", inspection[1].Name)
+
+		listener := inspection[0].Listeners[0]
+		assert.Equal(t, "github.com/attilabuti/eventemitter/v2.testInspectListener", listener.Function)
+		assert.True(t, strings.HasSuffix(listener.File, "inspect_test.go"))
+		assert.Equal(t, 11, listener.Line)
+		assert.Equal(t, "func(string)", listener.Signature)
+		assert.False(t, listener.Pointer)
+		assert.False(t, listener.Registered.Before(start))
+
+		if assert.Equal(t, 2, len(inspection[1].Listeners)) {
+			listener, pointer := inspection[1].Listeners[0], inspection[1].Listeners[1]
+			assert.Equal(t, "func(int, string)", listener.Signature)
+			assert.False(t, listener.Pointer)
+			assert.Equal(t, "", listener.Group)
+			assert.True(t, pointer.Pointer)
+			assert.Equal(t, "plugin", pointer.Group)
+			assert.Equal(t, listener.Function, pointer.Function)
+		}
+	}
+
+	output := inspection.String()
+	assert.True(t, strings.HasPrefix(output, "event_1 (1)\n\tgithub.com/attilabuti/eventemitter/v2.testInspectListener func(string) at "))
+	assert.Contains(t, output, "event_2 (2)\n")
+	assert.Contains(t, output, "\t*github.com/attilabuti/eventemitter/v2.TestInspect.func1 func(int, string) at ")
+	assert.Contains(t, output, " group=plugin ")
+
+	// Empty emitter.
+	assert.Equal(t, 0, len(New().Inspect()))
+	assert.Equal(t, "", New().Inspect().String())
+}
@@ -0,0 +1,111 @@
+package eventemitter
+
+import (
+	"expvar"
+	"time"
+)
+
+// Metrics receives instrumentation events from the emitter. Implementations
+// must be safe for concurrent use.
+type Metrics interface {
+	// Emitted is called once for every accepted emit of the event.
+	Emitted(eventName string)
+
+	// ListenerCalled is called after a listener of the event returned, with
+	// the time it took.
+	ListenerCalled(eventName string, duration time.Duration)
+
+	// ListenerPanicked is called when a listener of the event panics.
+	ListenerPanicked(eventName string)
+
+	// AsyncStarted and AsyncFinished are called when an asynchronous listener
+	// call starts and finishes, so their difference is the number of in-flight
+	// goroutines.
+	AsyncStarted(eventName string)
+	AsyncFinished(eventName string)
+
+	// Dropped is called when an emit of the event is rejected.
+	Dropped(eventName string, reason error)
+}
+
+// WithMetrics sets the instrumentation hooks of the emitter.
+func WithMetrics(metrics Metrics) Option {
+	return func(e *Emitter) {
+		e.metrics = metrics
+	}
+}
+
+// ExpvarMetrics is a Metrics implementation backed by expvar maps. It
+// implements expvar.Var, so it can be published with expvar.Publish(name, m).
+type ExpvarMetrics struct {
+	root      *expvar.Map
+	emits     *expvar.Map
+	calls     *expvar.Map
+	latencies *expvar.Map
+	panics    *expvar.Map
+	dropped   *expvar.Map
+	inflight  *expvar.Int
+}
+
+// NewExpvarMetrics returns a new, unpublished ExpvarMetrics.
+func NewExpvarMetrics() *ExpvarMetrics {
+	m := &ExpvarMetrics{
+		root:      new(expvar.Map).Init(),
+		emits:     new(expvar.Map).Init(),
+		calls:     new(expvar.Map).Init(),
+		latencies: new(expvar.Map).Init(),
+		panics:    new(expvar.Map).Init(),
+		dropped:   new(expvar.Map).Init(),
+		inflight:  new(expvar.Int),
+	}
+
+	m.root.Set("emits", m.emits)
+	m.root.Set("calls", m.calls)
+	m.root.Set("latency_ns", m.latencies)
+	m.root.Set("panics", m.panics)
+	m.root.Set("dropped", m.dropped)
+	m.root.Set("inflight", m.inflight)
+
+	return m
+}
+
+// Emitted increments the emit count of the event.
+func (m *ExpvarMetrics) Emitted(eventName string) {
+	m.emits.Add(eventName, 1)
+}
+
+// ListenerCalled increments the call count and the total latency of the event.
+func (m *ExpvarMetrics) ListenerCalled(eventName string, duration time.Duration) {
+	m.calls.Add(eventName, 1)
+	m.latencies.Add(eventName, int64(duration))
+}
+
+// ListenerPanicked increments the panic count of the event.
+func (m *ExpvarMetrics) ListenerPanicked(eventName string) {
+	m.panics.Add(eventName, 1)
+}
+
+// AsyncStarted increments the in-flight gauge.
+func (m *ExpvarMetrics) AsyncStarted(eventName string) {
+	m.inflight.Add(1)
+}
+
+// AsyncFinished decrements the in-flight gauge.
+func (m *ExpvarMetrics) AsyncFinished(eventName string) {
+	m.inflight.Add(-1)
+}
+
+// Dropped increments the dropped count of the event.
+func (m *ExpvarMetrics) Dropped(eventName string, reason error) {
+	m.dropped.Add(eventName, 1)
+}
+
+// Get returns the variable with the given name, e.g. "emits" or "inflight".
+func (m *ExpvarMetrics) Get(name string) expvar.Var {
+	return m.root.Get(name)
+}
+
+// String returns the metrics as JSON.
+func (m *ExpvarMetrics) String() string {
+	return m.root.String()
+}
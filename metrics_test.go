@@ -0,0 +1,114 @@
+package eventemitter
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMetrics struct {
+	mu       sync.Mutex
+	emits    map[string]int
+	calls    map[string]int
+	panics   map[string]int
+	dropped  map[string]int
+	inflight int
+}
+
+func newTestMetrics() *testMetrics {
+	return &testMetrics{
+		emits:   map[string]int{},
+		calls:   map[string]int{},
+		panics:  map[string]int{},
+		dropped: map[string]int{},
+	}
+}
+
+func (m *testMetrics) Emitted(eventName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.emits[eventName]++
+}
+
+func (m *testMetrics) ListenerCalled(eventName string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[eventName]++
+}
+
+func (m *testMetrics) ListenerPanicked(eventName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.panics[eventName]++
+}
+
+func (m *testMetrics) AsyncStarted(eventName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inflight++
+}
+
+func (m *testMetrics) AsyncFinished(eventName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inflight--
+}
+
+func (m *testMetrics) Dropped(eventName string, reason error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dropped[eventName]++
+}
+
+func TestMetrics(t *testing.T) {
+	metrics := newTestMetrics()
+	emitter := New(WithMetrics(metrics))
+
+	emitter.On("event", func(a int) {})
+	emitter.On("event", func(a int) {})
+	emitter.On("panic", func() { panic("listener") })
+
+	assert.NoError(t, emitter.EmitSync("event", 1))
+	assert.NoError(t, emitter.Emit("event", 2))
+	assert.PanicsWithValue(t, "listener", func() { emitter.EmitSync("panic") })
+	assert.Equal(t, ErrEventNotExists, emitter.EmitSync("missing"))
+
+	assert.NoError(t, emitter.Shutdown(context.Background()))
+	assert.Equal(t, ErrClosed, emitter.Emit("event", 3))
+
+	assert.Equal(t, 2, metrics.emits["event"])
+	assert.Equal(t, 4, metrics.calls["event"])
+	assert.Equal(t, 1, metrics.panics["panic"])
+	assert.Equal(t, 1, metrics.dropped["missing"])
+	assert.Equal(t, 1, metrics.dropped["event"])
+	assert.Equal(t, 0, metrics.inflight)
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	metrics := NewExpvarMetrics()
+	emitter := New(WithMetrics(metrics))
+
+	emitter.On("event", func() {})
+	emitter.EmitSync("event")
+	emitter.EmitSync("event")
+	emitter.EmitSync("missing")
+
+	var values struct {
+		Emits    map[string]int64 `json:"emits"`
+		Calls    map[string]int64 `json:"calls"`
+		Dropped  map[string]int64 `json:"dropped"`
+		Inflight int64            `json:"inflight"`
+	}
+	if assert.NoError(t, json.Unmarshal([]byte(metrics.String()), &values)) {
+		assert.Equal(t, int64(2), values.Emits["event"])
+		assert.Equal(t, int64(2), values.Calls["event"])
+		assert.Equal(t, int64(1), values.Dropped["missing"])
+		assert.Equal(t, int64(0), values.Inflight)
+	}
+
+	assert.NotNil(t, metrics.Get("latency_ns"))
+}
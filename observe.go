@@ -0,0 +1,58 @@
+package eventemitter
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// observer is an emit observer, compared by identity on removal.
+type observer struct {
+	fn func(ctx context.Context, eventName string, arguments []any)
+}
+
+// Observe calls the observer with the name and the arguments of every emit,
+// including the emits of events without listeners, before the listeners are
+// called. The observer must not modify the arguments.
+// Returns a function that removes the observer.
+func (e *Emitter) Observe(fn func(eventName string, arguments []any)) (remove func()) {
+	return e.ObserveContext(func(ctx context.Context, eventName string, arguments []any) {
+		fn(eventName, arguments)
+	})
+}
+
+// ObserveContext is like Observe, but the observer also receives the context
+// of the emits.
+func (e *Emitter) ObserveContext(fn func(ctx context.Context, eventName string, arguments []any)) (remove func()) {
+	o := &observer{fn: fn}
+
+	e.mu.Lock()
+	observers, _ := e.observers.Load().([]*observer)
+	e.observers.Store(append(append([]*observer(nil), observers...), o))
+	e.mu.Unlock()
+
+	var removed int32
+	return func() {
+		if !atomic.CompareAndSwapInt32(&removed, 0, 1) {
+			return
+		}
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		observers, _ := e.observers.Load().([]*observer)
+		remaining := make([]*observer, 0, len(observers))
+		for _, other := range observers {
+			if other != o {
+				remaining = append(remaining, other)
+			}
+		}
+		e.observers.Store(remaining)
+	}
+}
+
+func (e *Emitter) observe(ctx context.Context, eventName string, arguments []any) {
+	observers, _ := e.observers.Load().([]*observer)
+	for _, o := range observers {
+		o.fn(ctx, eventName, arguments)
+	}
+}
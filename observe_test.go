@@ -0,0 +1,59 @@
+package eventemitter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserve(t *testing.T) {
+	emitter := New()
+
+	type emit struct {
+		name string
+		args []any
+	}
+
+	var first, second []emit
+	removeFirst := emitter.Observe(func(eventName string, arguments []any) {
+		first = append(first, emit{eventName, arguments})
+	})
+	emitter.Observe(func(eventName string, arguments []any) {
+		second = append(second, emit{eventName, arguments})
+	})
+
+	emitter.On("event", func(a int) {})
+	emitter.EmitSync("event", 1)
+	emitter.EmitSync("missing", "test")
+	emitter.EmitSync("")
+
+	expected := []emit{{"event", []any{1}}, {"missing", []any{"test"}}}
+	assert.Equal(t, expected, first)
+	assert.Equal(t, expected, second)
+
+	// Remove an observer.
+	removeFirst()
+	removeFirst()
+	emitter.EmitSync("event", 2)
+	assert.Equal(t, 2, len(first))
+	assert.Equal(t, 3, len(second))
+}
+
+func TestObserveContext(t *testing.T) {
+	emitter := New()
+
+	type key struct{}
+
+	var values []any
+	remove := emitter.ObserveContext(func(ctx context.Context, eventName string, arguments []any) {
+		values = append(values, ctx.Value(key{}))
+	})
+
+	emitter.EmitSyncContext(context.WithValue(context.Background(), key{}, "value"), "event")
+	emitter.EmitSync("event")
+	remove()
+	emitter.EmitSync("event")
+
+	assert.Equal(t, []any{"value", nil}, values)
+}
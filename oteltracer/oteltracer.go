@@ -0,0 +1,77 @@
+/*
+Package oteltracer adapts OpenTelemetry tracing to the eventemitter.Tracer
+interface.
+
+Every emit starts a span named "emit <event>". Synchronous listener calls start
+a child span of the emit span, while asynchronous listener calls start a new
+trace linked to the emit span.
+*/
+package oteltracer
+
+import (
+	"context"
+
+	"github.com/attilabuti/eventemitter/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Name is the instrumentation name of the tracer.
+const Name = "github.com/attilabuti/eventemitter/v2/oteltracer"
+
+const eventKey = attribute.Key("event.name")
+
+type tracer struct {
+	tracer trace.Tracer
+}
+
+type span struct {
+	span trace.Span
+}
+
+// New returns an eventemitter.Tracer using the given tracer provider. If the
+// provider is nil, the global tracer provider is used.
+func New(provider trace.TracerProvider) eventemitter.Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	return &tracer{tracer: provider.Tracer(Name)}
+}
+
+func (t *tracer) StartEmit(ctx context.Context, eventName string) (context.Context, eventemitter.Span) {
+	ctx, s := t.tracer.Start(ctx, "emit "+eventName,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(eventKey.String(eventName)),
+	)
+
+	return ctx, &span{span: s}
+}
+
+func (t *tracer) StartListener(ctx context.Context, eventName string, async bool) (context.Context, eventemitter.Span) {
+	options := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(eventKey.String(eventName), attribute.Bool("event.async", async)),
+	}
+
+	// Asynchronous listeners outlive the emit, so they start a new trace
+	// linked to the emitting span.
+	if async {
+		options = append(options, trace.WithNewRoot(), trace.WithLinks(trace.LinkFromContext(ctx)))
+	}
+
+	ctx, s := t.tracer.Start(ctx, "listener "+eventName, options...)
+
+	return ctx, &span{span: s}
+}
+
+func (s *span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+
+	s.span.End()
+}
@@ -0,0 +1,86 @@
+package oteltracer
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/attilabuti/eventemitter/v2"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newEmitter() (*eventemitter.Emitter, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	return eventemitter.New(eventemitter.WithTracer(New(provider))), recorder
+}
+
+func TestSyncSpans(t *testing.T) {
+	emitter, recorder := newEmitter()
+
+	var listenerSpan trace.SpanContext
+	emitter.On("event", func(ctx context.Context, name string) {
+		listenerSpan = trace.SpanContextFromContext(ctx)
+	})
+
+	assert.NoError(t, emitter.EmitSync("event", "test"))
+
+	spans := recorder.Ended()
+	if assert.Equal(t, 2, len(spans)) {
+		listener, emit := spans[0], spans[1]
+
+		assert.Equal(t, "listener event", listener.Name())
+		assert.Equal(t, "emit event", emit.Name())
+		assert.Equal(t, emit.SpanContext().SpanID(), listener.Parent().SpanID())
+		assert.Equal(t, listener.SpanContext().SpanID(), listenerSpan.SpanID())
+	}
+}
+
+func TestAsyncSpans(t *testing.T) {
+	emitter, recorder := newEmitter()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	emitter.On("event", func() {
+		defer wg.Done()
+	})
+
+	assert.NoError(t, emitter.Emit("event"))
+	wg.Wait()
+	assert.NoError(t, emitter.Close())
+
+	spans := recorder.Ended()
+	if assert.Equal(t, 2, len(spans)) {
+		emit, listener := spans[0], spans[1]
+		if emit.Name() != "emit event" {
+			emit, listener = listener, emit
+		}
+
+		assert.False(t, listener.Parent().IsValid())
+		assert.NotEqual(t, emit.SpanContext().TraceID(), listener.SpanContext().TraceID())
+		if assert.Equal(t, 1, len(listener.Links())) {
+			assert.Equal(t, emit.SpanContext().SpanID(), listener.Links()[0].SpanContext.SpanID())
+		}
+	}
+}
+
+func TestPanicSpan(t *testing.T) {
+	emitter, recorder := newEmitter()
+
+	emitter.On("event", func() {
+		panic("listener")
+	})
+
+	assert.Panics(t, func() { emitter.EmitSync("event") })
+
+	spans := recorder.Ended()
+	if assert.Equal(t, 2, len(spans)) {
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Equal(t, "panic: listener", spans[0].Status().Description)
+	}
+}
@@ -0,0 +1,108 @@
+package eventemitter
+
+import "context"
+
+// relayKey is the context key of the events relayed by pipes.
+type relayKey struct{}
+
+// relayHop is an event relayed by a pipe.
+type relayHop struct {
+	emitter   *Emitter
+	eventName string
+}
+
+// Pipe relays the emits of the src event to the dst event of the emitter. The
+// arguments are reshaped by the transform function, which can also drop the
+// emit by returning false; a nil transform relays the arguments unchanged.
+// See Forward for the calls of the relayed events.
+// Returns a function that removes the pipe.
+func (e *Emitter) Pipe(src, dst string, transform func(args []any) ([]any, bool), options ...ListenerOption) (remove func(), err error) {
+	return e.relay(src, e, dst, transform, options)
+}
+
+// Forward relays the emits of the events named eventNames to the other
+// emitter, under the same names.
+//
+// Relayed events are emitted synchronously by the relay listener, with the
+// context of the source emit, and the errors of their listeners are returned
+// to the source emit. Events already relayed in the chain of pipes of the emit
+// are not relayed again, so pipes can form cycles. Relayed events without
+// listeners are ignored.
+// Returns a function that removes the forwarding.
+func (e *Emitter) Forward(other *Emitter, eventNames ...string) (remove func(), err error) {
+	removes := make([]func(), 0, len(eventNames))
+	remove = func() {
+		for _, remove := range removes {
+			remove()
+		}
+	}
+
+	for _, eventName := range eventNames {
+		r, err := e.relay(eventName, other, eventName, nil, nil)
+		if err != nil {
+			remove()
+			return nil, err
+		}
+
+		removes = append(removes, r)
+	}
+
+	return remove, nil
+}
+
+func (e *Emitter) relay(src string, target *Emitter, dst string, transform func(args []any) ([]any, bool), options []ListenerOption) (remove func(), err error) {
+	if len(dst) == 0 {
+		return nil, ErrEmptyName
+	}
+
+	// The listener is added by pointer, so it is removed by identity.
+	listener := func(ctx context.Context, args ...any) error {
+		ctx = withRelayHop(ctx, e, src)
+		if relayed(ctx, target, dst) {
+			return nil
+		}
+
+		if transform != nil {
+			var ok bool
+			if args, ok = transform(args); !ok {
+				return nil
+			}
+		}
+
+		if err := target.EmitSyncContext(ctx, dst, args...); err != nil && err != ErrEventNotExists {
+			return err
+		}
+
+		return nil
+	}
+
+	// Contexts emitted as the first argument are relayed as arguments.
+	options = append(options[:len(options):len(options)], WithEmitContext())
+	if err := e.AddListener(src, &listener, options...); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		e.RemoveListener(src, &listener)
+	}, nil
+}
+
+// relayed reports whether the event of the emitter was relayed in the chain
+// of pipes of the context.
+func relayed(ctx context.Context, emitter *Emitter, eventName string) bool {
+	hops, _ := ctx.Value(relayKey{}).([]relayHop)
+	for _, hop := range hops {
+		if hop.emitter == emitter && hop.eventName == eventName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func withRelayHop(ctx context.Context, emitter *Emitter, eventName string) context.Context {
+	hops, _ := ctx.Value(relayKey{}).([]relayHop)
+	hops = append(hops[:len(hops):len(hops)], relayHop{emitter: emitter, eventName: eventName})
+
+	return context.WithValue(ctx, relayKey{}, hops)
+}
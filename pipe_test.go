@@ -0,0 +1,104 @@
+package eventemitter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipe(t *testing.T) {
+	emitter := New()
+
+	var received []string
+	emitter.On("user.created", func(name string, admin bool) {
+		received = append(received, name)
+	})
+
+	remove, err := emitter.Pipe("signup", "user.created", func(args []any) ([]any, bool) {
+		if args[0] == "" {
+			return nil, false
+		}
+
+		return []any{args[0], false}, true
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, emitter.EmitSync("signup", "alice", "alice@example.com"))
+	assert.NoError(t, emitter.EmitSync("signup", "", "anonymous@example.com"))
+	assert.Equal(t, []string{"alice"}, received)
+
+	// Errors of the relayed listeners are returned to the source emit.
+	emitter.On("user.created", func(name string, admin bool) error {
+		return errors.New("failed")
+	})
+	assert.Error(t, emitter.EmitSync("signup", "bob", "bob@example.com"))
+
+	remove()
+	assert.Equal(t, ErrEventNotExists, emitter.EmitSync("signup", "carol", "carol@example.com"))
+	assert.Equal(t, []string{"alice", "bob"}, received)
+
+	_, err = emitter.Pipe("signup", "", nil)
+	assert.Equal(t, ErrEmptyName, err)
+
+	// Contexts emitted as arguments are relayed.
+	ctx := context.WithValue(context.Background(), testContextKey{}, "value")
+	emitter.On("dst", func(c context.Context, a int) {
+		assert.Equal(t, ctx, c)
+		assert.Equal(t, 1, a)
+	})
+	_, err = emitter.Pipe("src", "dst", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, emitter.EmitSync("src", ctx, 1))
+}
+
+func TestPipeCycle(t *testing.T) {
+	emitter := New()
+
+	calls := map[string]int{}
+	emitter.On("a", func(n int) { calls["a"]++ })
+	emitter.On("b", func(n int) { calls["b"]++ })
+
+	emitter.Pipe("a", "b", nil)
+	emitter.Pipe("b", "a", nil)
+	emitter.Pipe("a", "a", nil)
+
+	assert.NoError(t, emitter.EmitSync("a", 1))
+	assert.Equal(t, map[string]int{"a": 1, "b": 1}, calls)
+
+	assert.NoError(t, emitter.EmitSync("b", 1))
+	assert.Equal(t, map[string]int{"a": 2, "b": 2}, calls)
+}
+
+func TestForward(t *testing.T) {
+	first := New()
+	second := New()
+
+	var received []string
+	first.On("event", func(s string) { received = append(received, "first:"+s) })
+	second.On("event", func(s string) { received = append(received, "second:"+s) })
+	second.On("other", func(s string) { received = append(received, "other:"+s) })
+
+	remove, err := first.Forward(second, "event", "other")
+	assert.NoError(t, err)
+	_, err = second.Forward(first, "event")
+	assert.NoError(t, err)
+
+	assert.NoError(t, first.EmitSync("event", "1"))
+	assert.NoError(t, second.EmitSync("event", "2"))
+	assert.Equal(t, []string{"first:1", "second:1", "second:2", "first:2"}, received)
+
+	// Events without other listeners in the source are forwarded.
+	received = nil
+	assert.NoError(t, first.EmitSync("other", "3"))
+	assert.Equal(t, []string{"other:3"}, received)
+
+	remove()
+	received = nil
+	first.EmitSync("event", "4")
+	assert.Equal(t, []string{"first:4"}, received)
+
+	_, err = first.Forward(second, "")
+	assert.Equal(t, ErrEmptyName, err)
+}
@@ -0,0 +1,164 @@
+package eventemitter
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sync"
+	"time"
+)
+
+var ErrRateLimited = errors.New("Event rate limit exceeded")
+
+// RateLimitPolicy selects the handling of the emits exceeding a rate limit.
+type RateLimitPolicy uint8
+
+const (
+	RateLimitBlock RateLimitPolicy = iota // The emit waits until it is allowed.
+	RateLimitDrop                         // The emit is dropped silently.
+	RateLimitError                        // The emit is rejected with ErrRateLimited.
+)
+
+// rateLimit is a token bucket shared by the events matching its pattern.
+type rateLimit struct {
+	pattern string
+	rate    float64
+	burst   float64
+	policy  RateLimitPolicy
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// WithRateLimit limits the emits of the events matching the pattern to rate
+// emits per second, with bursts of up to burst emits. The pattern is either an
+// event name or a path.Match pattern, e.g. "order.*"; the matching events
+// share the limit. The emits exceeding the limit are handled according to the
+// policy. Dropped and rejected emits are reported to the metrics.
+func WithRateLimit(pattern string, rate float64, burst int, policy RateLimitPolicy) Option {
+	return func(e *Emitter) {
+		if rate <= 0 {
+			return
+		}
+
+		if burst < 1 {
+			burst = 1
+		}
+
+		e.limits = append(e.limits, &rateLimit{
+			pattern: pattern,
+			rate:    rate,
+			burst:   float64(burst),
+			policy:  policy,
+			tokens:  float64(burst),
+		})
+	}
+}
+
+func (l *rateLimit) matches(eventName string) bool {
+	if l.pattern == eventName {
+		return true
+	}
+
+	matched, _ := path.Match(l.pattern, eventName)
+
+	return matched
+}
+
+// reserve takes a token from the bucket. Returns the time to wait before the
+// emit is allowed, and false if it is not allowed without waiting and the
+// policy doesn't wait.
+func (l *rateLimit) reserve(now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0, true
+	}
+
+	if l.policy != RateLimitBlock {
+		return 0, false
+	}
+
+	// The token is taken in advance, so the waiting emits are queued.
+	l.tokens--
+
+	return time.Duration(-l.tokens / l.rate * float64(time.Second)), true
+}
+
+// cancel returns a reserved token.
+func (l *rateLimit) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens++
+}
+
+// limit applies the rate limits matching the event. A token is reserved from
+// every matching limit before the emit is allowed; the reserved tokens are
+// returned if one of the limits rejects the emit. Returns whether the emit is
+// allowed, and the error of the emit if it is not: ErrRateLimited if it is
+// rejected, nil if it is dropped, or the error of the context if it expired
+// while waiting.
+func (e *Emitter) limit(ctx context.Context, eventName string) (bool, error) {
+	var reserved []*rateLimit
+	cancel := func() {
+		for _, l := range reserved {
+			l.cancel()
+		}
+	}
+
+	now := time.Now()
+
+	var wait time.Duration
+	for _, l := range e.limits {
+		if !l.matches(eventName) {
+			continue
+		}
+
+		d, ok := l.reserve(now)
+		if !ok {
+			cancel()
+			e.dropped(eventName, ErrRateLimited)
+
+			if l.policy == RateLimitDrop {
+				return false, nil
+			}
+
+			return false, ErrRateLimited
+		}
+
+		reserved = append(reserved, l)
+		if d > wait {
+			wait = d
+		}
+	}
+
+	if wait <= 0 {
+		return true, nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true, nil
+	case <-ctx.Done():
+		cancel()
+		return false, ctx.Err()
+	case <-e.closing():
+		cancel()
+		return false, ErrClosed
+	}
+}
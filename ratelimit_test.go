@@ -0,0 +1,94 @@
+package eventemitter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitError(t *testing.T) {
+	metrics := newTestMetrics()
+	emitter := New(WithRateLimit("order.*", 1, 2, RateLimitError), WithMetrics(metrics))
+
+	called := 0
+	emitter.On("order.created", func() { called++ })
+	emitter.On("order.updated", func() { called++ })
+	emitter.On("user", func() { called++ })
+
+	// The matching events share the limit.
+	assert.NoError(t, emitter.EmitSync("order.created"))
+	assert.NoError(t, emitter.EmitSync("order.updated"))
+	assert.Equal(t, ErrRateLimited, emitter.EmitSync("order.created"))
+	assert.Equal(t, ErrRateLimited, emitter.Emit("order.updated"))
+	assert.Equal(t, 2, called)
+
+	// Other events are not limited.
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, emitter.EmitSync("user"))
+	}
+	assert.Equal(t, 7, called)
+
+	assert.Equal(t, 1, metrics.dropped["order.created"])
+	assert.Equal(t, 1, metrics.dropped["order.updated"])
+}
+
+func TestRateLimitDrop(t *testing.T) {
+	emitter := New(WithRateLimit("event", 1, 1, RateLimitDrop))
+
+	called := 0
+	emitter.On("event", func() { called++ })
+
+	assert.NoError(t, emitter.EmitSync("event"))
+	assert.NoError(t, emitter.EmitSync("event"))
+	assert.Equal(t, 1, called)
+
+	// Tokens are refilled over time.
+	l := emitter.limits[0]
+	now := time.Now()
+	_, ok := l.reserve(now)
+	assert.False(t, ok)
+	_, ok = l.reserve(now.Add(time.Second))
+	assert.True(t, ok)
+}
+
+func TestRateLimitOverlapping(t *testing.T) {
+	emitter := New(
+		WithRateLimit("order.*", 1, 3, RateLimitError),
+		WithRateLimit("order.created", 1, 1, RateLimitError),
+	)
+
+	emitter.On("order.created", func() {})
+	emitter.On("order.updated", func() {})
+
+	assert.NoError(t, emitter.EmitSync("order.created"))
+	assert.Equal(t, ErrRateLimited, emitter.EmitSync("order.created"))
+
+	// The token taken by the rejected emit is returned to the shared limit.
+	assert.NoError(t, emitter.EmitSync("order.updated"))
+	assert.NoError(t, emitter.EmitSync("order.updated"))
+	assert.Equal(t, ErrRateLimited, emitter.EmitSync("order.updated"))
+}
+
+func TestRateLimitBlock(t *testing.T) {
+	emitter := New(WithRateLimit("event", 50, 1, RateLimitBlock))
+
+	called := 0
+	emitter.On("event", func() { called++ })
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, emitter.EmitSync("event"))
+	}
+	assert.Equal(t, 3, called)
+	assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+
+	// The context expires while waiting.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	emitter.EmitSync("event")
+	assert.Equal(t, context.DeadlineExceeded, emitter.EmitSyncContext(ctx, "event"))
+	assert.Equal(t, 4, called)
+}
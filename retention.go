@@ -0,0 +1,120 @@
+package eventemitter
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+)
+
+// retention keeps the arguments of the last emits of an event.
+type retention struct {
+	mu     sync.Mutex
+	size   int
+	events [][]any
+}
+
+// WithRetention keeps the arguments of the last size emits of the events named
+// eventNames, including the emits without listeners. Listeners added with the
+// WithReplay option receive the retained emits.
+func WithRetention(size int, eventNames ...string) Option {
+	return func(e *Emitter) {
+		if size <= 0 {
+			return
+		}
+
+		if e.retained == nil {
+			e.retained = make(map[string]*retention, len(eventNames))
+		}
+
+		for _, eventName := range eventNames {
+			e.retained[eventName] = &retention{size: size}
+		}
+	}
+}
+
+// WithSticky keeps the arguments of the last emit of the events named
+// eventNames. It is an alias for WithRetention(1, eventNames...).
+func WithSticky(eventNames ...string) Option {
+	return WithRetention(1, eventNames...)
+}
+
+// WithReplay calls the listener, when it is added, with the retained emits of
+// the event, from the oldest to the newest. The calls are synchronous, and
+// their errors and panics are passed to the dead letter hook. The listener is
+// not added if it doesn't accept the retained emits. Emits concurrent with the
+// registration may be received before the retained ones.
+func WithReplay() ListenerOption {
+	return func(h *handler) {
+		h.replay = true
+	}
+}
+
+func (r *retention) add(arguments []any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.events) == r.size {
+		copy(r.events, r.events[1:])
+		r.events = r.events[:r.size-1]
+	}
+
+	r.events = append(r.events, append([]any(nil), arguments...))
+}
+
+func (r *retention) snapshot() [][]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([][]any(nil), r.events...)
+}
+
+// checkRetained checks the retained emits of the event against the listener
+// to replay them to.
+func (e *Emitter) checkRetained(eventName string, listener *handler) error {
+	r := e.retained[eventName]
+	if r == nil || !listener.replay {
+		return nil
+	}
+
+	for _, arguments := range r.snapshot() {
+		if listener.filtered(arguments) {
+			continue
+		}
+
+		if _, _, err := e.check(eventName, listener, arguments, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replayRetained calls the listener with the retained emits of the event.
+func (e *Emitter) replayRetained(eventName string, listener *handler) {
+	r := e.retained[eventName]
+	if r == nil || !listener.replay {
+		return
+	}
+
+	for _, arguments := range r.snapshot() {
+		if !listener.filtered(arguments) {
+			e.replayCall(eventName, listener, arguments)
+		}
+	}
+}
+
+// replayCall calls the listener with a retained emit. Errors and panics are
+// passed to the dead letter hook.
+func (e *Emitter) replayCall(eventName string, listener *handler, arguments []any) {
+	inv := invocation{eventName: eventName, listener: listener, arguments: arguments}
+	defer func() {
+		if r := recover(); r != nil {
+			e.deadLettered(inv, 1, &PanicError{Event: eventName, Value: r, Stack: debug.Stack()})
+		}
+	}()
+
+	inv, _ = e.prepare(eventName, listener, arguments, nil)
+	if attempts, err := e.deliver(context.Background(), inv, false); err != nil {
+		e.deadLettered(inv, attempts, err)
+	}
+}
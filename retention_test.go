@@ -0,0 +1,112 @@
+package eventemitter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSticky(t *testing.T) {
+	emitter := New(WithSticky("connection"))
+
+	// Emits without listeners are retained.
+	assert.Equal(t, ErrEventNotExists, emitter.EmitSync("connection", "connecting"))
+	assert.Equal(t, ErrEventNotExists, emitter.EmitSync("connection", "connected"))
+
+	states := []string{}
+	emitter.On("connection", func(state string) {
+		states = append(states, state)
+	}, WithReplay())
+	assert.Equal(t, []string{"connected"}, states)
+
+	assert.NoError(t, emitter.EmitSync("connection", "disconnected"))
+	assert.Equal(t, []string{"connected", "disconnected"}, states)
+
+	// Listeners without the replay option.
+	called := false
+	emitter.On("connection", func(state string) {
+		called = true
+	})
+	assert.False(t, called)
+}
+
+func TestRetention(t *testing.T) {
+	emitter := New(WithRetention(3, "event"), WithRetention(0, "none"))
+
+	for i := 1; i <= 5; i++ {
+		emitter.EmitSync("event", i, "test")
+		emitter.EmitSync("other", i)
+		emitter.EmitSync("none", i)
+	}
+
+	received := []int{}
+	emitter.On("event", func(a int, b string) {
+		received = append(received, a)
+	}, WithReplay())
+	assert.Equal(t, []int{3, 4, 5}, received)
+
+	// Events that are not retained.
+	emitter.On("other", func(a int) {
+		assert.Fail(t, "not retained event replayed")
+	}, WithReplay())
+	emitter.On("none", func(a int) {
+		assert.Fail(t, "not retained event replayed")
+	}, WithReplay())
+
+	// Retained arguments are copied.
+	args := []any{6, "test"}
+	emitter.EmitSync("event", args...)
+	args[0] = 7
+
+	received = []int{}
+	emitter.On("event", func(a int, b string) {
+		received = append(received, a)
+	}, WithReplay())
+	assert.Equal(t, []int{4, 5, 6}, received)
+
+	// Wrong arguments.
+	count, _ := emitter.ListenerCount("event")
+	err := emitter.On("event", func(a string, b string) {}, WithReplay())
+	assert.EqualError(t, err, createTypeErr("event", 1, "string", "int"))
+	after, _ := emitter.ListenerCount("event")
+	assert.Equal(t, count, after)
+}
+
+func TestReplayDeadLetter(t *testing.T) {
+	var deadLetters []DeadLetter
+	emitter := New(WithSticky("event"), WithDeadLetter(func(d DeadLetter) {
+		deadLetters = append(deadLetters, d)
+	}))
+
+	errListener := errors.New("listener")
+	emitter.EmitSync("event", 1)
+	emitter.On("event", func(a int) error {
+		return errListener
+	}, WithReplay())
+
+	if assert.Equal(t, 1, len(deadLetters)) {
+		assert.Equal(t, []any{1}, deadLetters[0].Arguments)
+		assert.Equal(t, errListener, deadLetters[0].Err)
+	}
+}
+
+func TestReplayPanic(t *testing.T) {
+	var deadLetters []DeadLetter
+	emitter := New(WithSticky("event"), WithDeadLetter(func(d DeadLetter) {
+		deadLetters = append(deadLetters, d)
+	}))
+
+	emitter.EmitSync("event", 1)
+	err := emitter.On("event", func(a int) {
+		panic("replay")
+	}, WithReplay())
+	assert.NoError(t, err)
+
+	if assert.Equal(t, 1, len(deadLetters)) {
+		var panicErr *PanicError
+		if assert.ErrorAs(t, deadLetters[0].Err, &panicErr) {
+			assert.Equal(t, "replay", panicErr.Value)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package eventemitter
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy configures the retries of a listener returning an error.
+type RetryPolicy struct {
+	// MaxAttempts is the number of calls, including the first one.
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry.
+	Backoff time.Duration
+
+	// MaxBackoff limits the delay between retries, if positive.
+	MaxBackoff time.Duration
+
+	// Multiplier is the growth factor of the delay between retries. Values
+	// less than 1 mean 2.
+	Multiplier float64
+
+	// Jitter randomizes the delay by the fraction, between 0 and 1, of it.
+	Jitter float64
+
+	// Retryable reports whether the error is retried. If nil, every error is
+	// retried.
+	Retryable func(err error) bool
+}
+
+// DeadLetter describes a failed listener call, after its retries, if any,
+// whose error cannot be returned to the emitter, e.g. of an asynchronous emit.
+type DeadLetter struct {
+	Event     string
+	Arguments []any
+	Listener  string // Name of the listener function.
+	Err       error  // Error of the last attempt.
+	Attempts  int
+}
+
+// WithRetry retries the listener according to the policy if it returns an
+// error. Synchronous emits wait for the retries, while asynchronous emits
+// retry in the background and pass exhausted failures to the dead letter hook.
+func WithRetry(policy RetryPolicy) ListenerOption {
+	return func(h *handler) {
+		h.retry = &policy
+	}
+}
+
+// WithDeadLetter sets the hook receiving the failed asynchronous listener
+// calls.
+func WithDeadLetter(hook func(DeadLetter)) Option {
+	return func(e *Emitter) {
+		e.deadLetter = hook
+	}
+}
+
+// delay returns the delay before the retry following the given attempt.
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	delay := float64(p.Backoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+// callRetry calls the listener, retrying it according to its retry policy.
+// The retries stop early if the context is done or the emitter is closed.
+// Returns the number of attempts and the error of the last attempt.
+func (e *Emitter) callRetry(ctx context.Context, inv invocation, async bool) (int, error) {
+	policy := inv.listener.retry
+
+	for attempt := 1; ; attempt++ {
+		var err error
+		if async {
+			err = e.call(ctx, inv, true)
+		} else {
+			err = e.callTimeout(ctx, inv)
+		}
+
+		if err == nil || policy == nil || attempt >= policy.MaxAttempts {
+			return attempt, err
+		}
+
+		if policy.Retryable != nil && !policy.Retryable(err) {
+			return attempt, err
+		}
+
+		timer := time.NewTimer(policy.delay(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, err
+		case <-e.closing():
+			timer.Stop()
+			return attempt, err
+		}
+	}
+}
+
+func (e *Emitter) deadLettered(inv invocation, attempts int, err error) {
+	if e.deadLetter == nil {
+		return
+	}
+
+	e.deadLetter(DeadLetter{
+		Event:     inv.eventName,
+		Arguments: inv.arguments,
+		Listener:  inv.listener.info().Function,
+		Err:       err,
+		Attempts:  attempts,
+	})
+}
@@ -0,0 +1,129 @@
+package eventemitter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	emitter := New()
+
+	errTemporary := errors.New("temporary")
+	errPermanent := errors.New("permanent")
+
+	// Retried until it succeeds.
+	attempts := 0
+	emitter.On("event", func() error {
+		attempts++
+		if attempts < 3 {
+			return errTemporary
+		}
+
+		return nil
+	}, WithRetry(RetryPolicy{MaxAttempts: 5, Backoff: time.Millisecond}))
+	assert.NoError(t, emitter.EmitSync("event"))
+	assert.Equal(t, 3, attempts)
+
+	// Retried until the attempts are exhausted.
+	attempts = 0
+	emitter.On("exhausted", func() error {
+		attempts++
+		return errTemporary
+	}, WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}))
+	err := emitter.EmitSync("exhausted")
+	var emitErr *EmitError
+	if assert.ErrorAs(t, err, &emitErr) {
+		assert.Equal(t, []error{errTemporary}, emitErr.Errors)
+	}
+	assert.Equal(t, 3, attempts)
+
+	// Errors that are not retryable.
+	attempts = 0
+	emitter.On("permanent", func() error {
+		attempts++
+		return errPermanent
+	}, WithRetry(RetryPolicy{
+		MaxAttempts: 3,
+		Retryable:   func(err error) bool { return err != errPermanent },
+	}))
+	assert.Error(t, emitter.EmitParallel("permanent"))
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRetryDeadLetter(t *testing.T) {
+	deadLetters := make(chan DeadLetter, 1)
+	emitter := New(WithDeadLetter(func(d DeadLetter) {
+		deadLetters <- d
+	}))
+
+	errListener := errors.New("listener")
+	emitter.On("event", func(a int) error {
+		return errListener
+	}, WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, Jitter: 0.5}))
+	emitter.On("event", func(a int) error {
+		return nil
+	})
+
+	assert.NoError(t, emitter.Emit("event", 42))
+
+	d := <-deadLetters
+	assert.Equal(t, "event", d.Event)
+	assert.Equal(t, []any{42}, d.Arguments)
+	assert.Equal(t, "github.com/attilabuti/eventemitter/v2.TestRetryDeadLetter.func2", d.Listener)
+	assert.Equal(t, errListener, d.Err)
+	assert.Equal(t, 3, d.Attempts)
+
+	// Failures without a retry policy.
+	emitter.On("no_retry", func() error {
+		return errListener
+	})
+	assert.NoError(t, emitter.Emit("no_retry"))
+	assert.Equal(t, 1, (<-deadLetters).Attempts)
+
+	assert.NoError(t, emitter.Close())
+	assert.Equal(t, 0, len(deadLetters))
+}
+
+func TestRetryShutdown(t *testing.T) {
+	deadLetters := make(chan DeadLetter, 1)
+	emitter := New(WithDeadLetter(func(d DeadLetter) {
+		deadLetters <- d
+	}))
+
+	called := make(chan struct{}, 1)
+	emitter.On("event", func() error {
+		called <- struct{}{}
+		return errors.New("listener")
+	}, WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}))
+
+	assert.NoError(t, emitter.Emit("event"))
+	<-called
+
+	// Shutdown stops waiting for the next retry.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	assert.NoError(t, emitter.Shutdown(ctx))
+	assert.Equal(t, 1, (<-deadLetters).Attempts)
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{Backoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	assert.Equal(t, 10*time.Millisecond, policy.delay(1))
+	assert.Equal(t, 20*time.Millisecond, policy.delay(2))
+	assert.Equal(t, 40*time.Millisecond, policy.delay(3))
+	assert.Equal(t, 50*time.Millisecond, policy.delay(4))
+
+	policy = RetryPolicy{Backoff: 10 * time.Millisecond, Multiplier: 3}
+	assert.Equal(t, 90*time.Millisecond, policy.delay(3))
+
+	policy = RetryPolicy{Backoff: 100 * time.Millisecond, Jitter: 0.1}
+	for i := 0; i < 100; i++ {
+		delay := policy.delay(1)
+		assert.True(t, delay >= 90*time.Millisecond && delay <= 110*time.Millisecond)
+	}
+}
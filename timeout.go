@@ -0,0 +1,80 @@
+package eventemitter
+
+import (
+	"context"
+	"time"
+)
+
+// WithTimeout limits the time the listener can run in EmitSync and
+// EmitParallel. When the timeout is exceeded, the context passed to the
+// listener is canceled, and the emit reports a *ListenerTimeoutError and moves
+// on without waiting for the listener to return.
+func WithTimeout(timeout time.Duration) ListenerOption {
+	return func(h *handler) {
+		h.timeout = timeout
+	}
+}
+
+// WithListenerTimeout sets the timeout of the listeners added without the
+// WithTimeout option.
+func WithListenerTimeout(timeout time.Duration) Option {
+	return func(e *Emitter) {
+		e.timeout = timeout
+	}
+}
+
+type callResult struct {
+	err      error
+	panicked bool
+	value    any
+}
+
+// callTimeout calls the listener, limiting its running time to its timeout.
+// Panics of the listener are propagated if it returns in time.
+func (e *Emitter) callTimeout(ctx context.Context, inv invocation) error {
+	timeout := inv.listener.timeout
+	if timeout == 0 {
+		timeout = e.timeout
+	}
+
+	// The listener is run in a tracked goroutine, so Shutdown waits for it.
+	if timeout <= 0 || !e.startAsync() {
+		return e.call(ctx, inv, false)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	done := make(chan callResult, 1)
+
+	go func() {
+		defer e.inflight.Done()
+		defer cancel()
+
+		defer func() {
+			if r := recover(); r != nil {
+				done <- callResult{panicked: true, value: r}
+			}
+		}()
+
+		done <- callResult{err: e.call(ctx, inv, false)}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-done:
+		if result.panicked {
+			panic(result.value)
+		}
+
+		return result.err
+	case <-timer.C:
+		cancel()
+
+		return &ListenerTimeoutError{
+			Event:    inv.eventName,
+			Listener: inv.listener.info().Function,
+			Timeout:  timeout,
+		}
+	}
+}
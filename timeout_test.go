@@ -0,0 +1,79 @@
+package eventemitter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	emitter := New()
+
+	canceled := make(chan error, 1)
+	emitter.On("event", func(ctx context.Context) {
+		<-ctx.Done()
+		canceled <- ctx.Err()
+	}, WithTimeout(10*time.Millisecond))
+
+	called := false
+	emitter.On("event", func() {
+		called = true
+	})
+
+	// The emit reports the timeout and moves on.
+	err := emitter.EmitSync("event")
+	var emitErr *EmitError
+	if assert.ErrorAs(t, err, &emitErr) && assert.Equal(t, 1, len(emitErr.Errors)) {
+		var timeoutErr *ListenerTimeoutError
+		if assert.ErrorAs(t, emitErr.Errors[0], &timeoutErr) {
+			assert.Equal(t, "event", timeoutErr.Event)
+			assert.Equal(t, "github.com/attilabuti/eventemitter/v2.TestTimeout.func1", timeoutErr.Listener)
+			assert.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+			assert.Equal(t, "Listener github.com/attilabuti/eventemitter/v2.TestTimeout.func1 of event event timed out after 10ms", timeoutErr.Error())
+		}
+	}
+	assert.True(t, called)
+	assert.Error(t, <-canceled)
+
+	// Listeners returning in time.
+	errListener := errors.New("listener")
+	emitter.On("in_time", func(a int) error { return errListener }, WithTimeout(time.Second))
+	err = emitter.EmitSync("in_time", 1)
+	if assert.ErrorAs(t, err, &emitErr) {
+		assert.Equal(t, []error{errListener}, emitErr.Errors)
+	}
+
+	emitter.On("panic", func() { panic("listener") }, WithTimeout(time.Second))
+	assert.PanicsWithValue(t, "listener", func() { emitter.EmitSync("panic") })
+}
+
+func TestListenerTimeout(t *testing.T) {
+	emitter := New(WithListenerTimeout(10 * time.Millisecond))
+
+	release := make(chan struct{})
+	returned := false
+	emitter.On("event", func() {
+		<-release
+		returned = true
+	})
+	emitter.On("event", func() {}, WithTimeout(time.Second))
+	emitter.On("event", func() { time.Sleep(100 * time.Millisecond) }, WithTimeout(20*time.Millisecond))
+
+	err := emitter.EmitParallel("event")
+	var emitErr *EmitError
+	if assert.ErrorAs(t, err, &emitErr) && assert.Equal(t, 2, len(emitErr.Errors)) {
+		assert.Equal(t, 10*time.Millisecond, emitErr.Errors[0].(*ListenerTimeoutError).Timeout)
+		assert.Equal(t, 20*time.Millisecond, emitErr.Errors[1].(*ListenerTimeoutError).Timeout)
+	}
+
+	// Shutdown waits for the timed out listeners.
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	assert.NoError(t, emitter.Shutdown(ctx))
+	assert.True(t, returned)
+}
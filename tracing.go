@@ -0,0 +1,28 @@
+package eventemitter
+
+import "context"
+
+// Tracer starts spans for emits and listener calls. The context returned by
+// StartEmit is passed to StartListener, so asynchronous listener spans can be
+// linked to the emitting span. Implementations must be safe for concurrent use.
+type Tracer interface {
+	// StartEmit starts a span for an emit of the event.
+	StartEmit(ctx context.Context, eventName string) (context.Context, Span)
+
+	// StartListener starts a span for a listener call of the event. The
+	// returned context is passed to listeners accepting a context.
+	StartListener(ctx context.Context, eventName string, async bool) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// End ends the span, with the error of the call, if any.
+	End(err error)
+}
+
+// WithTracer sets the tracer of the emitter.
+func WithTracer(tracer Tracer) Option {
+	return func(e *Emitter) {
+		e.tracer = tracer
+	}
+}
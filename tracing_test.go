@@ -0,0 +1,99 @@
+package eventemitter
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testSpanKey struct{}
+
+type testSpan struct {
+	tracer *testTracer
+	name   string
+	parent string
+	async  bool
+	err    error
+	ended  bool
+}
+
+func (s *testSpan) End(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	s.err = err
+	s.ended = true
+}
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) start(ctx context.Context, name string, async bool) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	span := &testSpan{tracer: t, name: name, async: async}
+	if parent, ok := ctx.Value(testSpanKey{}).(*testSpan); ok {
+		span.parent = parent.name
+	}
+	t.spans = append(t.spans, span)
+
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func (t *testTracer) StartEmit(ctx context.Context, eventName string) (context.Context, Span) {
+	return t.start(ctx, "emit "+eventName, false)
+}
+
+func (t *testTracer) StartListener(ctx context.Context, eventName string, async bool) (context.Context, Span) {
+	return t.start(ctx, "listener "+eventName, async)
+}
+
+func TestTracer(t *testing.T) {
+	tracer := &testTracer{}
+	emitter := New(WithTracer(tracer))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	var listenerSpan *testSpan
+	emitter.On("event", func(ctx context.Context, a int) {
+		defer wg.Done()
+
+		listenerSpan, _ = ctx.Value(testSpanKey{}).(*testSpan)
+		assert.Equal(t, 1, a)
+	})
+
+	assert.NoError(t, emitter.EmitContext(context.Background(), "event", 1))
+	wg.Wait()
+	emitter.Close()
+
+	if assert.Equal(t, 2, len(tracer.spans)) {
+		assert.Equal(t, "emit event", tracer.spans[0].name)
+		assert.Equal(t, "listener event", tracer.spans[1].name)
+		assert.Equal(t, "emit event", tracer.spans[1].parent)
+		assert.True(t, tracer.spans[1].async)
+		assert.Same(t, tracer.spans[1], listenerSpan)
+
+		for _, span := range tracer.spans {
+			assert.True(t, span.ended)
+			assert.NoError(t, span.err)
+		}
+	}
+
+	// Panics end the span with an error.
+	tracer = &testTracer{}
+	emitter = New(WithTracer(tracer))
+	emitter.On("panic", func() { panic("listener") })
+
+	assert.Panics(t, func() { emitter.EmitSync("panic") })
+	if assert.Equal(t, 2, len(tracer.spans)) {
+		assert.False(t, tracer.spans[1].async)
+		assert.EqualError(t, tracer.spans[1].err, "panic: listener")
+		assert.True(t, tracer.spans[0].ended)
+	}
+}